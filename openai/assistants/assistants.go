@@ -1,14 +1,13 @@
 package assistants
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"github.com/ilborsch/openai-go/openai/assistants/messages"
 	"github.com/ilborsch/openai-go/openai/assistants/runs"
 	"github.com/ilborsch/openai-go/openai/assistants/threads"
 	vecstores "github.com/ilborsch/openai-go/openai/assistants/vector-stores"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"github.com/ilborsch/openai-go/openai/schema"
 	"net/http"
 )
 
@@ -16,13 +15,18 @@ const (
 	DefaultModel        = "gpt-3.5-turbo"
 	ToolFileSearch      = "file_search"
 	ToolCodeInterpreter = "code_interpreter"
+	ToolFunction        = "function"
 )
 
 type AssistantClient interface {
 	CreateAssistant(name, instructions, vectorStoreID string, tools []Tool) (string, error)
+	CreateAssistantContext(ctx context.Context, name, instructions, vectorStoreID string, tools []Tool) (string, error)
 	GetAssistant(ID string) (GetAssistantResponse, error)
+	GetAssistantContext(ctx context.Context, ID string) (GetAssistantResponse, error)
 	Modify(assistantID, newInstructions, newModel string, newTemperature float32) error
+	ModifyContext(ctx context.Context, assistantID, newInstructions, newModel string, newTemperature float32) error
 	DeleteAssistant(ID string) error
+	DeleteAssistantContext(ctx context.Context, ID string) error
 	vecstores.VectorStoreClient
 	messages.MessageClient
 	runs.RunClient
@@ -33,12 +37,14 @@ type AssistantClient interface {
 // The subdomains may be accessed by full composition relation ( f.e. `OpenAI.Assistants.VectorStores.Create(...)` ),
 // or alternatively by shortened syntax ( f.e. `OpenAI.VectorStores.Create(...)` ).
 // Both examples do absolutely the same, it's just a syntax sugar from Go language.
+// Create, Get, List, Update and Delete are the resource-style methods of the assistant domain itself.
 type Assistants struct {
-	APIKey string
-	vecstores.VectorStoreClient
-	messages.MessageClient
-	runs.RunClient
-	threads.ThreadClient
+	APIKey    string
+	Transport *transport.Client
+	*vecstores.VectorStores
+	*messages.Messages
+	*runs.Runs
+	*threads.Threads
 }
 
 // CreateAssistantRequest is used to unmarshal OpenAI API response in the Create function
@@ -62,7 +68,32 @@ type VectorStoreIDs struct {
 
 // Tool is used to marshal a payload for the Create function
 type Tool struct {
-	Type string `json:"type"`
+	Type     string              `json:"type"`
+	Function *FunctionDefinition `json:"function,omitempty"`
+}
+
+// FunctionDefinition describes a function the assistant may call, see NewFunctionTool
+type FunctionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON Schema of the arguments object, f.e. generated with the schema package
+	Parameters any  `json:"parameters,omitempty"`
+	Strict     bool `json:"strict,omitempty"`
+}
+
+// NewFunctionTool returns a function tool. Strict mode is enabled when `parameters` is
+// a *schema.Schema that supports it.
+func NewFunctionTool(name, description string, parameters any) Tool {
+	s, ok := parameters.(*schema.Schema)
+	return Tool{
+		Type: ToolFunction,
+		Function: &FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+			Strict:      ok && s != nil && s.Strict(),
+		},
+	}
 }
 
 // CreateAssistantResponse is used to unmarshal OpenAI API response in the Create function
@@ -72,6 +103,7 @@ type CreateAssistantResponse struct {
 
 // GetAssistantResponse is used to unmarshal OpenAI API response in the GetAssistant function
 type GetAssistantResponse struct {
+	ID           string  `json:"id"`
 	Name         string  `json:"name"`
 	Model        string  `json:"model"`
 	Instructions string  `json:"instructions"`
@@ -79,15 +111,23 @@ type GetAssistantResponse struct {
 	Temperature  float32 `json:"temperature"`
 }
 
+// ListAssistantsResponse is used to unmarshal OpenAI API response in the List function
+type ListAssistantsResponse struct {
+	Assistants []GetAssistantResponse `json:"data"`
+}
+
 // CreateAssistant creates an assistant with name, instructions, tools and a Vector Store specified by vectorStoreID.
 // Argument `tools` may be passed as a `nil`. In this case ToolFileSearch will be used as a default
 func (a Assistants) CreateAssistant(name, instructions, vectorStoreID string, tools []Tool) (string, error) {
-	const URL = "https://api.openai.com/v1/assistants"
+	return a.CreateAssistantContext(context.Background(), name, instructions, vectorStoreID, tools)
+}
 
+// CreateAssistantContext is like CreateAssistant but uses `ctx` to cancel the outgoing request.
+func (a Assistants) CreateAssistantContext(ctx context.Context, name, instructions, vectorStoreID string, tools []Tool) (string, error) {
 	assistantConfig := CreateAssistantRequest{
 		Name:         name,
 		Instructions: instructions,
-		Model:        DefaultModel,
+		Model:        a.api().ModelName(DefaultModel),
 		Tools:        tools,
 		ToolResources: ToolResources{
 			FileSearch: VectorStoreIDs{
@@ -101,36 +141,16 @@ func (a Assistants) CreateAssistant(name, instructions, vectorStoreID string, to
 		}
 	}
 
-	requestBody, err := json.Marshal(&assistantConfig)
-	if err != nil {
-		return "", err
-	}
-	request, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return "", err
-	}
-
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", "Bearer "+a.APIKey)
-	request.Header.Set("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return string(responseBody), fmt.Errorf("reponse status code: %v %s ", resp.StatusCode, string(responseBody))
-	}
-
 	var response CreateAssistantResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
+	err := a.api().Call(ctx, transport.Request{
+		Endpoint:   "assistants.create",
+		Method:     http.MethodPost,
+		Path:       "/assistants",
+		Body:       assistantConfig,
+		Assistants: true,
+		Idempotent: true,
+	}, &response)
+	if err != nil {
 		return "", err
 	}
 	return response.AssistantID, nil
@@ -138,37 +158,19 @@ func (a Assistants) CreateAssistant(name, instructions, vectorStoreID string, to
 
 // GetAssistant gets assistant information by its ID
 func (a Assistants) GetAssistant(ID string) (GetAssistantResponse, error) {
-	URL := "https://api.openai.com/v1/assistants/" + ID
-	request, err := http.NewRequest(http.MethodGet, URL, nil)
-	if err != nil {
-		return GetAssistantResponse{}, err
-	}
-
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", "Bearer "+a.APIKey)
-	request.Header.Set("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return GetAssistantResponse{}, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return GetAssistantResponse{}, fmt.Errorf("response status code: %v", resp.StatusCode)
-	}
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return GetAssistantResponse{}, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return GetAssistantResponse{}, fmt.Errorf("error creating assistant: %v %s", resp.StatusCode, string(responseBody))
-	}
+	return a.GetAssistantContext(context.Background(), ID)
+}
 
+// GetAssistantContext is like GetAssistant but uses `ctx` to cancel the outgoing request.
+func (a Assistants) GetAssistantContext(ctx context.Context, ID string) (GetAssistantResponse, error) {
 	var response GetAssistantResponse
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	err := a.api().Call(ctx, transport.Request{
+		Endpoint:   "assistants.retrieve",
+		Method:     http.MethodGet,
+		Path:       "/assistants/" + ID,
+		Assistants: true,
+	}, &response)
+	if err != nil {
 		return GetAssistantResponse{}, err
 	}
 	return response, nil
@@ -177,70 +179,109 @@ func (a Assistants) GetAssistant(ID string) (GetAssistantResponse, error) {
 // Modify updates assistant information.
 // Arguments that are left blank will not be modified
 func (a Assistants) Modify(assistantID, newInstructions, newModel string, newTemperature float32) error {
-	URL := "https://api.openai.com/v1/assistants/" + assistantID
+	return a.ModifyContext(context.Background(), assistantID, newInstructions, newModel, newTemperature)
+}
 
+// ModifyContext is like Modify but uses `ctx` to cancel the outgoing request.
+func (a Assistants) ModifyContext(ctx context.Context, assistantID, newInstructions, newModel string, newTemperature float32) error {
 	requestMap := make(map[string]any)
 	if newInstructions != "" {
 		requestMap["instructions"] = newInstructions
 	}
 	if newModel != "" {
-		requestMap["model"] = newModel
+		requestMap["model"] = a.api().ModelName(newModel)
 	}
 	if newTemperature != 0.0 {
 		requestMap["temperature"] = newTemperature
 	}
 
-	requestBody, err := json.Marshal(requestMap)
-	request, err := http.NewRequest(http.MethodPost, URL, bytes.NewReader(requestBody))
-	if err != nil {
-		return err
-	}
+	return a.api().Call(ctx, transport.Request{
+		Endpoint:   "assistants.update",
+		Method:     http.MethodPost,
+		Path:       "/assistants/" + assistantID,
+		Body:       requestMap,
+		Assistants: true,
+	}, nil)
+}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", "Bearer "+a.APIKey)
-	request.Header.Set("OpenAI-Beta", "assistants=v2")
+// DeleteAssistant removes assistant from OpenAI portal by its ID.
+func (a Assistants) DeleteAssistant(ID string) error {
+	return a.DeleteAssistantContext(context.Background(), ID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// DeleteAssistantContext is like DeleteAssistant but uses `ctx` to cancel the outgoing request.
+func (a Assistants) DeleteAssistantContext(ctx context.Context, ID string) error {
+	return a.api().Call(ctx, transport.Request{
+		Endpoint:   "assistants.delete",
+		Method:     http.MethodDelete,
+		Path:       "/assistants/" + ID,
+		Assistants: true,
+	}, nil)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error modifying assistant: %v %s", resp.StatusCode, string(responseBody))
-	}
-	return nil
+// Create is the resource-style name of CreateAssistant
+func (a Assistants) Create(name, instructions, vectorStoreID string, tools []Tool) (string, error) {
+	return a.CreateAssistantContext(context.Background(), name, instructions, vectorStoreID, tools)
 }
 
-// DeleteAssistant removes assistant from OpenAI portal by its ID.
-func (a Assistants) DeleteAssistant(ID string) error {
-	req, err := http.NewRequest("DELETE", "https://api.openai.com/v1/assistants/"+ID, nil)
-	if err != nil {
-		return err
-	}
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (a Assistants) CreateContext(ctx context.Context, name, instructions, vectorStoreID string, tools []Tool) (string, error) {
+	return a.CreateAssistantContext(ctx, name, instructions, vectorStoreID, tools)
+}
 
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", "Bearer "+a.APIKey)
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
+// Get is the resource-style name of GetAssistant
+func (a Assistants) Get(ID string) (GetAssistantResponse, error) {
+	return a.GetAssistantContext(context.Background(), ID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// GetContext is like Get but uses `ctx` to cancel the outgoing request.
+func (a Assistants) GetContext(ctx context.Context, ID string) (GetAssistantResponse, error) {
+	return a.GetAssistantContext(ctx, ID)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// List returns the assistants of the organization, newest first
+func (a Assistants) List() (ListAssistantsResponse, error) {
+	return a.ListContext(context.Background())
+}
+
+// ListContext is like List but uses `ctx` to cancel the outgoing request.
+func (a Assistants) ListContext(ctx context.Context) (ListAssistantsResponse, error) {
+	var response ListAssistantsResponse
+	err := a.api().Call(ctx, transport.Request{
+		Endpoint:   "assistants.list",
+		Method:     http.MethodGet,
+		Path:       "/assistants",
+		Assistants: true,
+	}, &response)
 	if err != nil {
-		return err
+		return ListAssistantsResponse{}, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error deleting assistant: %v %s", resp.StatusCode, string(responseBody))
+	return response, nil
+}
+
+// Update is the resource-style name of Modify
+func (a Assistants) Update(assistantID, newInstructions, newModel string, newTemperature float32) error {
+	return a.ModifyContext(context.Background(), assistantID, newInstructions, newModel, newTemperature)
+}
+
+// UpdateContext is like Update but uses `ctx` to cancel the outgoing request.
+func (a Assistants) UpdateContext(ctx context.Context, assistantID, newInstructions, newModel string, newTemperature float32) error {
+	return a.ModifyContext(ctx, assistantID, newInstructions, newModel, newTemperature)
+}
+
+// Delete is the resource-style name of DeleteAssistant
+func (a Assistants) Delete(ID string) error {
+	return a.DeleteAssistantContext(context.Background(), ID)
+}
+
+// DeleteContext is like Delete but uses `ctx` to cancel the outgoing request.
+func (a Assistants) DeleteContext(ctx context.Context, ID string) error {
+	return a.DeleteAssistantContext(ctx, ID)
+}
+
+func (a Assistants) api() *transport.Client {
+	if a.Transport != nil {
+		return a.Transport
 	}
-	return nil
+	return &transport.Client{APIKey: a.APIKey}
 }
@@ -1,10 +1,9 @@
 package messages
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"net/http"
 )
 
@@ -15,13 +14,18 @@ const (
 
 type MessageClient interface {
 	AddMessageToThread(threadID string, message string) error
+	AddMessageToThreadContext(ctx context.Context, threadID string, message string) error
 	GetThreadMessages(threadID string) (ThreadMessages, error)
+	GetThreadMessagesContext(ctx context.Context, threadID string) (ThreadMessages, error)
 	LatestAssistantResponse(threadID string) (string, error)
+	LatestAssistantResponseContext(ctx context.Context, threadID string) (string, error)
 }
 
-// Messages represents OpenAI API thread message domain
+// Messages represents OpenAI API thread message domain.
+// Create and List are the resource-style methods of the domain.
 type Messages struct {
-	APIKey string
+	APIKey    string
+	Transport *transport.Client
 }
 
 // AddMessageRequest is used to structure payload in the AddMessageToThread request
@@ -58,73 +62,40 @@ type MessageValue struct {
 // AddMessageToThread adds user message to a thread.
 // The thread is specified by the threadID argument
 func (m Messages) AddMessageToThread(threadID string, message string) error {
-	URL := fmt.Sprintf("https://api.openai.com/v1/threads/%s/messages", threadID)
+	return m.AddMessageToThreadContext(context.Background(), threadID, message)
+}
 
+// AddMessageToThreadContext is like AddMessageToThread but uses `ctx` to cancel the outgoing request.
+func (m Messages) AddMessageToThreadContext(ctx context.Context, threadID string, message string) error {
 	payload := AddMessageRequest{
 		Role:    "user",
 		Content: message,
 	}
-	requestBody, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.APIKey)
-	req.Header.Set("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to create message: %d %s ", resp.StatusCode, string(responseBody))
-	}
-	return nil
+	return m.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.messages.create",
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/threads/%s/messages", threadID),
+		Body:       payload,
+		Assistants: true,
+		Idempotent: true,
+	}, nil)
 }
 
 // GetThreadMessages returns a list of all messages that are stored in a thread as a ThreadMessages struct
 func (m Messages) GetThreadMessages(threadID string) (ThreadMessages, error) {
-	URL := fmt.Sprintf("https://api.openai.com/v1/threads/%s/messages", threadID)
-
-	req, err := http.NewRequest(http.MethodGet, URL, nil)
-	if err != nil {
-		return ThreadMessages{}, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.APIKey)
-	req.Header.Set("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return ThreadMessages{}, fmt.Errorf("request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ThreadMessages{}, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return ThreadMessages{}, fmt.Errorf("failed to retrieve messages: %d %s", resp.StatusCode, string(responseBody))
-	}
+	return m.GetThreadMessagesContext(context.Background(), threadID)
+}
 
+// GetThreadMessagesContext is like GetThreadMessages but uses `ctx` to cancel the outgoing request.
+func (m Messages) GetThreadMessagesContext(ctx context.Context, threadID string) (ThreadMessages, error) {
 	var response ThreadMessages
-	if err = json.Unmarshal(responseBody, &response); err != nil {
+	err := m.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.messages.list",
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/threads/%s/messages", threadID),
+		Assistants: true,
+	}, &response)
+	if err != nil {
 		return ThreadMessages{}, err
 	}
 	return response, nil
@@ -133,7 +104,12 @@ func (m Messages) GetThreadMessages(threadID string) (ThreadMessages, error) {
 // LatestAssistantResponse does almost same job as GetThreadMessages except that it extracts last assistant response from the thread messages list.
 // See https://github.com/ilborsch/openai-go/blob/main/examples/cli-chat-bot-assistant.go for an example of how to use it correctly
 func (m Messages) LatestAssistantResponse(threadID string) (string, error) {
-	chatStory, err := m.GetThreadMessages(threadID)
+	return m.LatestAssistantResponseContext(context.Background(), threadID)
+}
+
+// LatestAssistantResponseContext is like LatestAssistantResponse but passes `ctx` to GetThreadMessagesContext.
+func (m Messages) LatestAssistantResponseContext(ctx context.Context, threadID string) (string, error) {
+	chatStory, err := m.GetThreadMessagesContext(ctx, threadID)
 	if err != nil {
 		return "", err
 	}
@@ -147,3 +123,30 @@ func (m Messages) LatestAssistantResponse(threadID string) (string, error) {
 	}
 	return "", fmt.Errorf("thread %s has no assistant responses", threadID)
 }
+
+// Create is the resource-style name of AddMessageToThread
+func (m Messages) Create(threadID string, message string) error {
+	return m.AddMessageToThreadContext(context.Background(), threadID, message)
+}
+
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (m Messages) CreateContext(ctx context.Context, threadID string, message string) error {
+	return m.AddMessageToThreadContext(ctx, threadID, message)
+}
+
+// List is the resource-style name of GetThreadMessages
+func (m Messages) List(threadID string) (ThreadMessages, error) {
+	return m.GetThreadMessagesContext(context.Background(), threadID)
+}
+
+// ListContext is like List but uses `ctx` to cancel the outgoing request.
+func (m Messages) ListContext(ctx context.Context, threadID string) (ThreadMessages, error) {
+	return m.GetThreadMessagesContext(ctx, threadID)
+}
+
+func (m Messages) api() *transport.Client {
+	if m.Transport != nil {
+		return m.Transport
+	}
+	return &transport.Client{APIKey: m.APIKey}
+}
@@ -1,10 +1,9 @@
 package runs
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"net/http"
 )
 
@@ -21,12 +20,16 @@ const (
 
 type RunClient interface {
 	CreateRun(threadID string, assistantID string) (string, error)
+	CreateRunContext(ctx context.Context, threadID string, assistantID string) (string, error)
 	GetRun(threadID, runID string) (GetRunResponse, error)
+	GetRunContext(ctx context.Context, threadID, runID string) (GetRunResponse, error)
 }
 
-// Runs represents OpenAI API run domain
+// Runs represents OpenAI API run domain.
+// Create, Get and List are the resource-style methods of the domain.
 type Runs struct {
-	APIKey string
+	APIKey    string
+	Transport *transport.Client
 }
 
 // CreateRunRequest is used to structure payload in the Create function
@@ -41,51 +44,39 @@ type CreateRunResponse struct {
 
 // GetRunResponse is used to structure payload in the GetRun function
 type GetRunResponse struct {
+	ID          string `json:"id"`
 	Status      string `json:"status"`
 	ThreadID    string `json:"thread_id"`
 	AssistantID string `json:"assistant_id"`
 }
 
+// ListRunsResponse is used to unmarshal OpenAI API response in the List function
+type ListRunsResponse struct {
+	Runs []GetRunResponse `json:"data"`
+}
+
 // CreateRun creates run object for an assistant with the `assistantID` in the thread specified by `threadID`.
 // Returns its ID.
 // Recommended docs to better understand this approach: https://platform.openai.com/docs/assistants/overview
 func (r Runs) CreateRun(threadID string, assistantID string) (string, error) {
-	URL := fmt.Sprintf("https://api.openai.com/v1/threads/%s/runs", threadID)
+	return r.CreateRunContext(context.Background(), threadID, assistantID)
+}
 
+// CreateRunContext is like CreateRun but uses `ctx` to cancel the outgoing request.
+func (r Runs) CreateRunContext(ctx context.Context, threadID string, assistantID string) (string, error) {
 	payload := CreateRunRequest{
 		AssistantID: assistantID,
 	}
-	requestBody, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, URL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+r.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %v", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to create run: %d %s", resp.StatusCode, string(responseBody))
-	}
-
 	var response CreateRunResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
+	err := r.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.runs.create",
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/threads/%s/runs", threadID),
+		Body:       payload,
+		Assistants: true,
+		Idempotent: true,
+	}, &response)
+	if err != nil {
 		return "", err
 	}
 	return response.RunID, nil
@@ -93,34 +84,67 @@ func (r Runs) CreateRun(threadID string, assistantID string) (string, error) {
 
 // GetRun fetches run object given by `runID` parameter.
 func (r Runs) GetRun(threadID, runID string) (GetRunResponse, error) {
-	URL := fmt.Sprintf("https://api.openai.com/v1/threads/%s/runs/%s", threadID, runID)
+	return r.GetRunContext(context.Background(), threadID, runID)
+}
 
-	req, err := http.NewRequest(http.MethodGet, URL, nil)
+// GetRunContext is like GetRun but uses `ctx` to cancel the outgoing request.
+func (r Runs) GetRunContext(ctx context.Context, threadID, runID string) (GetRunResponse, error) {
+	var response GetRunResponse
+	err := r.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.runs.retrieve",
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/threads/%s/runs/%s", threadID, runID),
+		Assistants: true,
+	}, &response)
 	if err != nil {
 		return GetRunResponse{}, err
 	}
+	return response, nil
+}
 
-	req.Header.Set("Authorization", "Bearer "+r.APIKey)
-	req.Header.Set("OpenAI-Beta", "assistants=v2")
+// Create is the resource-style name of CreateRun
+func (r Runs) Create(threadID string, assistantID string) (string, error) {
+	return r.CreateRunContext(context.Background(), threadID, assistantID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return GetRunResponse{}, err
-	}
-	defer resp.Body.Close()
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (r Runs) CreateContext(ctx context.Context, threadID string, assistantID string) (string, error) {
+	return r.CreateRunContext(ctx, threadID, assistantID)
+}
+
+// Get is the resource-style name of GetRun
+func (r Runs) Get(threadID, runID string) (GetRunResponse, error) {
+	return r.GetRunContext(context.Background(), threadID, runID)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// GetContext is like Get but uses `ctx` to cancel the outgoing request.
+func (r Runs) GetContext(ctx context.Context, threadID, runID string) (GetRunResponse, error) {
+	return r.GetRunContext(ctx, threadID, runID)
+}
+
+// List returns the runs of the thread specified by `threadID`, newest first
+func (r Runs) List(threadID string) (ListRunsResponse, error) {
+	return r.ListContext(context.Background(), threadID)
+}
+
+// ListContext is like List but uses `ctx` to cancel the outgoing request.
+func (r Runs) ListContext(ctx context.Context, threadID string) (ListRunsResponse, error) {
+	var response ListRunsResponse
+	err := r.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.runs.list",
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/threads/%s/runs", threadID),
+		Assistants: true,
+	}, &response)
 	if err != nil {
-		return GetRunResponse{}, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return GetRunResponse{}, fmt.Errorf("error retrieving run: %v %s ", resp.StatusCode, string(responseBody))
+		return ListRunsResponse{}, err
 	}
+	return response, nil
+}
 
-	var response GetRunResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return GetRunResponse{}, fmt.Errorf("failed to marshal response body: %v", err)
+func (r Runs) api() *transport.Client {
+	if r.Transport != nil {
+		return r.Transport
 	}
-	return response, nil
+	return &transport.Client{APIKey: r.APIKey}
 }
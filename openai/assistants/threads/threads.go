@@ -1,19 +1,21 @@
 package threads
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"net/http"
 )
 
 type ThreadClient interface {
 	CreateThread() (string, error)
+	CreateThreadContext(ctx context.Context) (string, error)
 }
 
-// Threads represents OpenAI API thread domain
+// Threads represents OpenAI API thread domain.
+// Create, Get and Delete are the resource-style methods of the domain.
 type Threads struct {
-	APIKey string
+	APIKey    string
+	Transport *transport.Client
 }
 
 // CreateThreadResponse is used to unmarshal OpenAI API response
@@ -21,38 +23,83 @@ type CreateThreadResponse struct {
 	ThreadID string `json:"id"`
 }
 
+// Thread is used to unmarshal OpenAI API response in the Get function
+type Thread struct {
+	ID        string            `json:"id"`
+	CreatedAt int64             `json:"created_at"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
 // CreateThread creates an empty thread object.
 // Returns its ID
 func (t Threads) CreateThread() (string, error) {
-	const URL = "https://api.openai.com/v1/threads"
+	return t.CreateThreadContext(context.Background())
+}
 
-	request, err := http.NewRequest(http.MethodPost, URL, nil)
+// CreateThreadContext is like CreateThread but uses `ctx` to cancel the outgoing request.
+func (t Threads) CreateThreadContext(ctx context.Context) (string, error) {
+	var response CreateThreadResponse
+	err := t.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.create",
+		Method:     http.MethodPost,
+		Path:       "/threads",
+		Assistants: true,
+		Idempotent: true,
+	}, &response)
 	if err != nil {
 		return "", err
 	}
+	return response.ThreadID, nil
+}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", "Bearer "+t.APIKey)
-	request.Header.Set("OpenAI-Beta", "assistants=v2")
+// Create is the resource-style name of CreateThread
+func (t Threads) Create() (string, error) {
+	return t.CreateThreadContext(context.Background())
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (t Threads) CreateContext(ctx context.Context) (string, error) {
+	return t.CreateThreadContext(ctx)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// Get returns the thread object specified by `threadID`
+func (t Threads) Get(threadID string) (Thread, error) {
+	return t.GetContext(context.Background(), threadID)
+}
+
+// GetContext is like Get but uses `ctx` to cancel the outgoing request.
+func (t Threads) GetContext(ctx context.Context, threadID string) (Thread, error) {
+	var thread Thread
+	err := t.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.retrieve",
+		Method:     http.MethodGet,
+		Path:       "/threads/" + threadID,
+		Assistants: true,
+	}, &thread)
 	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error creating thread: %v %s ", resp.StatusCode, string(responseBody))
+		return Thread{}, err
 	}
+	return thread, nil
+}
 
-	var response CreateThreadResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
-		return "", err
+// Delete deletes the thread specified by `threadID` with all its messages
+func (t Threads) Delete(threadID string) error {
+	return t.DeleteContext(context.Background(), threadID)
+}
+
+// DeleteContext is like Delete but uses `ctx` to cancel the outgoing request.
+func (t Threads) DeleteContext(ctx context.Context, threadID string) error {
+	return t.api().Call(ctx, transport.Request{
+		Endpoint:   "threads.delete",
+		Method:     http.MethodDelete,
+		Path:       "/threads/" + threadID,
+		Assistants: true,
+	}, nil)
+}
+
+func (t Threads) api() *transport.Client {
+	if t.Transport != nil {
+		return t.Transport
 	}
-	return response.ThreadID, nil
+	return &transport.Client{APIKey: t.APIKey}
 }
@@ -1,24 +1,31 @@
 package vecstores
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"net/http"
 )
 
 type VectorStoreClient interface {
 	CreateVectorStore(storeName string) (string, error)
+	CreateVectorStoreContext(ctx context.Context, storeName string) (string, error)
 	DeleteVectorStore(storeID string) error
+	DeleteVectorStoreContext(ctx context.Context, storeID string) error
 	AddVectorStoreFile(storeID, fileID string) error
+	AddVectorStoreFileContext(ctx context.Context, storeID, fileID string) error
 	GetVectorStoreFiles(storeID string) (GetVectorStoreFilesResponse, error)
+	GetVectorStoreFilesContext(ctx context.Context, storeID string) (GetVectorStoreFilesResponse, error)
 	DeleteVectorStoreFile(storeID, fileID string) error
+	DeleteVectorStoreFileContext(ctx context.Context, storeID, fileID string) error
 }
 
-// VectorStores represents OpenAI API vector store domain
+// VectorStores represents OpenAI API vector store domain.
+// Besides the methods of VectorStoreClient it has resource-style methods,
+// f.e. `client.VectorStores.Create(...)` does the same as `client.CreateVectorStore(...)`.
 type VectorStores struct {
-	APIKey string
+	APIKey    string
+	Transport *transport.Client
 }
 
 // CreateVectorStoreResponse is used to unmarshal OpenAI API response in Create function
@@ -26,6 +33,30 @@ type CreateVectorStoreResponse struct {
 	ID string `json:"id"`
 }
 
+// CreateVectorStoreRequest is used to structure payload in the CreateVectorStore function
+type CreateVectorStoreRequest struct {
+	Name string `json:"name"`
+}
+
+// AddVectorStoreFileRequest is used to structure payload in the AddVectorStoreFile function
+type AddVectorStoreFileRequest struct {
+	FileID string `json:"file_id"`
+}
+
+// VectorStore is used to unmarshal OpenAI API response in the Get and List functions
+type VectorStore struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	CreatedAt  int64  `json:"created_at"`
+	UsageBytes int64  `json:"usage_bytes"`
+}
+
+// ListVectorStoresResponse is used to unmarshal OpenAI API response in the List function
+type ListVectorStoresResponse struct {
+	VectorStores []VectorStore `json:"data"`
+}
+
 // File is used to unmarshal OpenAI API response in GetFiles function
 type File struct {
 	FileID string `json:"id"`
@@ -40,36 +71,21 @@ type GetVectorStoreFilesResponse struct {
 // It is a new feature of assistants v2 API so I sincerely recommend to jump through this docs:
 // https://platform.openai.com/docs/api-reference/vector-stores/object
 func (v VectorStores) CreateVectorStore(storeName string) (string, error) {
-	URL := "https://api.openai.com/v1/vector_stores"
-	payload := fmt.Sprintf(`{"name": "%s"}`, storeName)
-	requestBody := bytes.NewBuffer([]byte(payload))
-
-	req, err := http.NewRequest("POST", URL, requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+v.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Error creating vector store: %v %s ", resp.StatusCode, string(responseBody))
-	}
+	return v.CreateVectorStoreContext(context.Background(), storeName)
+}
 
+// CreateVectorStoreContext is like CreateVectorStore but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) CreateVectorStoreContext(ctx context.Context, storeName string) (string, error) {
 	var response CreateVectorStoreResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
+	err := v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.create",
+		Method:     http.MethodPost,
+		Path:       "/vector_stores",
+		Body:       CreateVectorStoreRequest{Name: storeName},
+		Assistants: true,
+		Idempotent: true,
+	}, &response)
+	if err != nil {
 		return "", err
 	}
 	return response.ID, nil
@@ -77,127 +93,164 @@ func (v VectorStores) CreateVectorStore(storeName string) (string, error) {
 
 // DeleteVectorStore deletes the Vector Store object specified by `storeID` from OpenAI platform.
 func (v VectorStores) DeleteVectorStore(storeID string) error {
-	URL := fmt.Sprintf("https://api.openai.com/v1/vector_stores/%s", storeID)
-	req, err := http.NewRequest("DELETE", URL, nil)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+v.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	return v.DeleteVectorStoreContext(context.Background(), storeID)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete vector store: %v %s", resp.Status, string(responseBody))
-	}
-	return nil
+// DeleteVectorStoreContext is like DeleteVectorStore but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) DeleteVectorStoreContext(ctx context.Context, storeID string) error {
+	return v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.delete",
+		Method:     http.MethodDelete,
+		Path:       "/vector_stores/" + storeID,
+		Assistants: true,
+	}, nil)
 }
 
 // AddVectorStoreFile adds a file with `fileID` to the Vector Store object specified by `storeID` from OpenAI platform.
 func (v VectorStores) AddVectorStoreFile(storeID, fileID string) error {
-	URL := fmt.Sprintf("https://api.openai.com/v1/vector_stores/%s/files", storeID)
-	jsonData := fmt.Sprintf(`{"file_id": "%s"}`, fileID)
-	reqBody := bytes.NewBufferString(jsonData)
-
-	req, err := http.NewRequest("POST", URL, reqBody)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Authorization", "Bearer "+v.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+	return v.AddVectorStoreFileContext(context.Background(), storeID, fileID)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error adding file: %v %s ", resp.StatusCode, string(responseBody))
-	}
-	return nil
+// AddVectorStoreFileContext is like AddVectorStoreFile but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) AddVectorStoreFileContext(ctx context.Context, storeID, fileID string) error {
+	return v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.files.create",
+		Method:     http.MethodPost,
+		Path:       fmt.Sprintf("/vector_stores/%s/files", storeID),
+		Body:       AddVectorStoreFileRequest{FileID: fileID},
+		Assistants: true,
+		Idempotent: true,
+	}, nil)
 }
 
 // GetVectorStoreFiles returns a list of Files stored in the Vector Store object specified by `storeID` as a struct GetVectorStoreFilesResponse
 func (v VectorStores) GetVectorStoreFiles(storeID string) (GetVectorStoreFilesResponse, error) {
-	URL := fmt.Sprintf("https://api.openai.com/v1/vector_stores/%s/files", storeID)
+	return v.GetVectorStoreFilesContext(context.Background(), storeID)
+}
 
-	req, err := http.NewRequest("GET", URL, nil)
+// GetVectorStoreFilesContext is like GetVectorStoreFiles but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) GetVectorStoreFilesContext(ctx context.Context, storeID string) (GetVectorStoreFilesResponse, error) {
+	var filesResponse GetVectorStoreFilesResponse
+	err := v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.files.list",
+		Method:     http.MethodGet,
+		Path:       fmt.Sprintf("/vector_stores/%s/files", storeID),
+		Assistants: true,
+	}, &filesResponse)
 	if err != nil {
 		return GetVectorStoreFilesResponse{}, err
 	}
+	return filesResponse, nil
+}
+
+// DeleteVectorStoreFile is used to delete a file from a Vector Store object specified by `storeID`
+func (v VectorStores) DeleteVectorStoreFile(storeID, fileID string) error {
+	return v.DeleteVectorStoreFileContext(context.Background(), storeID, fileID)
+}
+
+// DeleteVectorStoreFileContext is like DeleteVectorStoreFile but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) DeleteVectorStoreFileContext(ctx context.Context, storeID, fileID string) error {
+	return v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.files.delete",
+		Method:     http.MethodDelete,
+		Path:       fmt.Sprintf("/vector_stores/%s/files/%s", storeID, fileID),
+		Assistants: true,
+	}, nil)
+}
 
-	req.Header.Add("Authorization", "Bearer "+v.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
+// Create is the resource-style name of CreateVectorStore
+func (v VectorStores) Create(storeName string) (string, error) {
+	return v.CreateVectorStoreContext(context.Background(), storeName)
+}
+
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) CreateContext(ctx context.Context, storeName string) (string, error) {
+	return v.CreateVectorStoreContext(ctx, storeName)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// Get returns the Vector Store object specified by `storeID`
+func (v VectorStores) Get(storeID string) (VectorStore, error) {
+	return v.GetContext(context.Background(), storeID)
+}
+
+// GetContext is like Get but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) GetContext(ctx context.Context, storeID string) (VectorStore, error) {
+	var store VectorStore
+	err := v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.retrieve",
+		Method:     http.MethodGet,
+		Path:       "/vector_stores/" + storeID,
+		Assistants: true,
+	}, &store)
 	if err != nil {
-		return GetVectorStoreFilesResponse{}, err
+		return VectorStore{}, err
 	}
-	defer resp.Body.Close()
+	return store, nil
+}
+
+// List returns the Vector Store objects of the organization, newest first
+func (v VectorStores) List() (ListVectorStoresResponse, error) {
+	return v.ListContext(context.Background())
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// ListContext is like List but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) ListContext(ctx context.Context) (ListVectorStoresResponse, error) {
+	var response ListVectorStoresResponse
+	err := v.api().Call(ctx, transport.Request{
+		Endpoint:   "vector_stores.list",
+		Method:     http.MethodGet,
+		Path:       "/vector_stores",
+		Assistants: true,
+	}, &response)
 	if err != nil {
-		return GetVectorStoreFilesResponse{}, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return GetVectorStoreFilesResponse{}, fmt.Errorf("error retrieving files: %v %s", resp.StatusCode, string(responseBody))
+		return ListVectorStoresResponse{}, err
 	}
+	return response, nil
+}
 
-	var filesResponse GetVectorStoreFilesResponse
-	if err = json.Unmarshal(responseBody, &filesResponse); err != nil {
-		return GetVectorStoreFilesResponse{}, err
-	}
+// Delete is the resource-style name of DeleteVectorStore
+func (v VectorStores) Delete(storeID string) error {
+	return v.DeleteVectorStoreContext(context.Background(), storeID)
+}
 
-	return filesResponse, nil
+// DeleteContext is like Delete but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) DeleteContext(ctx context.Context, storeID string) error {
+	return v.DeleteVectorStoreContext(ctx, storeID)
 }
 
-// DeleteVectorStoreFile is used to delete a file from a Vector Store object specified by `storeID`
-func (v VectorStores) DeleteVectorStoreFile(storeID, fileID string) error {
-	URL := fmt.Sprintf("https://api.openai.com/v1/vector_stores/%s/files/%s", storeID, fileID)
+// AddFile is the resource-style name of AddVectorStoreFile
+func (v VectorStores) AddFile(storeID, fileID string) error {
+	return v.AddVectorStoreFileContext(context.Background(), storeID, fileID)
+}
 
-	req, err := http.NewRequest("DELETE", URL, nil)
-	if err != nil {
-		return err
-	}
+// AddFileContext is like AddFile but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) AddFileContext(ctx context.Context, storeID, fileID string) error {
+	return v.AddVectorStoreFileContext(ctx, storeID, fileID)
+}
 
-	req.Header.Add("Authorization", "Bearer "+v.APIKey)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("OpenAI-Beta", "assistants=v2")
+// GetFiles is the resource-style name of GetVectorStoreFiles
+func (v VectorStores) GetFiles(storeID string) (GetVectorStoreFilesResponse, error) {
+	return v.GetVectorStoreFilesContext(context.Background(), storeID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+// GetFilesContext is like GetFiles but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) GetFilesContext(ctx context.Context, storeID string) (GetVectorStoreFilesResponse, error) {
+	return v.GetVectorStoreFilesContext(ctx, storeID)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error removing file: %v %s ", resp.StatusCode, string(responseBody))
+// DeleteFile is the resource-style name of DeleteVectorStoreFile
+func (v VectorStores) DeleteFile(storeID, fileID string) error {
+	return v.DeleteVectorStoreFileContext(context.Background(), storeID, fileID)
+}
+
+// DeleteFileContext is like DeleteFile but uses `ctx` to cancel the outgoing request.
+func (v VectorStores) DeleteFileContext(ctx context.Context, storeID, fileID string) error {
+	return v.DeleteVectorStoreFileContext(ctx, storeID, fileID)
+}
+
+func (v VectorStores) api() *transport.Client {
+	if v.Transport != nil {
+		return v.Transport
 	}
-	return nil
+	return &transport.Client{APIKey: v.APIKey}
 }
@@ -1,11 +1,10 @@
 package chatgpt
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"github.com/ilborsch/openai-go/openai/chatgpt/message"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"net/http"
 )
 
@@ -15,80 +14,248 @@ const (
 
 type ChatGPTClient interface {
 	CreateCompletion(chatStory []message.Message) (string, error)
+	CreateCompletionContext(ctx context.Context, chatStory []message.Message) (string, error)
+	CreateChatCompletion(ctx context.Context, request ChatCompletionRequest) (ChatCompletion, error)
+	CreateChatCompletionStream(ctx context.Context, request ChatCompletionRequest) (*ChatCompletionStream, error)
 }
 
+// ChatCompleter is the only method of ChatGPTClient a ToolRunner and CreateStructured use,
+// so wrappers and mocks passed to them don't have to implement the others
+type ChatCompleter interface {
+	CreateChatCompletion(ctx context.Context, request ChatCompletionRequest) (ChatCompletion, error)
+}
+
+// ChatGPT represents OpenAI API chat completions domain.
+// Create is the resource-style name of CreateCompletion.
 type ChatGPT struct {
-	APIKey string
-	Model  string
+	APIKey    string
+	Model     string
+	Transport *transport.Client
 }
 
-// CreateCompletionRequest is used to create a payload in the CreateCompletion function
-type CreateCompletionRequest struct {
+// ChatCompletionRequest is the payload of the CreateChatCompletion function.
+// Optional parameters left nil or empty are not sent, so OpenAI API defaults apply;
+// use Ptr to set numeric parameters, f.e. `Temperature: chatgpt.Ptr(0.0)`.
+// See https://platform.openai.com/docs/api-reference/chat/create for the meaning of every parameter.
+type ChatCompletionRequest struct {
+	// Model defaults to the model of the ChatGPT client
 	Model    string            `json:"model"`
 	Messages []message.Message `json:"messages"`
+
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	// MaxCompletionTokens limits the generated tokens, including reasoning tokens
+	MaxCompletionTokens *int `json:"max_completion_tokens,omitempty"`
+	// MaxTokens is the deprecated predecessor of MaxCompletionTokens, still required by some compatible APIs
+	MaxTokens *int `json:"max_tokens,omitempty"`
+	// N is the number of choices to generate
+	N           *int           `json:"n,omitempty"`
+	Seed        *int64         `json:"seed,omitempty"`
+	Stop        []string       `json:"stop,omitempty"`
+	LogitBias   map[string]int `json:"logit_bias,omitempty"`
+	Logprobs    *bool          `json:"logprobs,omitempty"`
+	TopLogprobs *int           `json:"top_logprobs,omitempty"`
+	// User is a stable identifier of the end user, helping OpenAI to detect abuse
+	User string `json:"user,omitempty"`
+	// ServiceTier is one of "auto", "default", "flex" or "priority"
+	ServiceTier string `json:"service_tier,omitempty"`
+	// ReasoningEffort is one of "minimal", "low", "medium" or "high" for reasoning models
+	ReasoningEffort string            `json:"reasoning_effort,omitempty"`
+	Store           *bool             `json:"store,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+
+	// Tools are the functions the model may call instead of answering, see NewFunctionTool
+	Tools      []Tool      `json:"tools,omitempty"`
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+	// ParallelToolCalls allows the model to request several tool calls in one reply (true by default)
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+	// ResponseFormat makes the model reply with JSON, see CreateStructured
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// Stream is set by CreateChatCompletionStream
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+// StreamOptions configures CreateChatCompletionStream
+type StreamOptions struct {
+	// IncludeUsage adds a final chunk with Usage and no choices to the stream
+	IncludeUsage bool `json:"include_usage"`
 }
 
-// CompletionChoice is used to unmarshal OpenAI API response in the CreateCompletion function
+// CreateCompletionRequest is the former name of ChatCompletionRequest
+type CreateCompletionRequest = ChatCompletionRequest
+
+// Ptr returns a pointer to `v`, to set optional parameters of ChatCompletionRequest
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Finish reasons of a CompletionChoice
+const (
+	FinishReasonStop          = "stop"
+	FinishReasonLength        = "length"
+	FinishReasonContentFilter = "content_filter"
+	FinishReasonToolCalls     = "tool_calls"
+)
+
+// ChatCompletion is a chat completion returned by the CreateChatCompletion function.
+// See https://platform.openai.com/docs/api-reference/chat/object
+type ChatCompletion struct {
+	ID                string             `json:"id"`
+	Object            string             `json:"object"`
+	Created           int64              `json:"created"`
+	Model             string             `json:"model"`
+	SystemFingerprint string             `json:"system_fingerprint"`
+	ServiceTier       string             `json:"service_tier"`
+	Choices           []CompletionChoice `json:"choices"`
+	Usage             Usage              `json:"usage"`
+}
+
+// CreateCompletionResponse is the former name of ChatCompletion
+type CreateCompletionResponse = ChatCompletion
+
+// CompletionChoice is one of the ChatCompletion choices, there are several of them when ChatCompletionRequest.N > 1
 type CompletionChoice struct {
 	Index   int `json:"index"`
 	Message `json:"message"`
+	// FinishReason tells why the model stopped, f.e. FinishReasonLength when the output was truncated
+	// by MaxCompletionTokens or FinishReasonContentFilter when it was flagged
+	FinishReason string `json:"finish_reason"`
+	// Logprobs are set when ChatCompletionRequest.Logprobs is true
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+}
+
+// Logprobs are the log probabilities of the generated tokens of a choice
+type Logprobs struct {
+	Content []TokenLogprob `json:"content"`
+	Refusal []TokenLogprob `json:"refusal"`
 }
 
-// CreateCompletionResponse is used to unmarshal OpenAI API response in the CreateCompletion function
-type CreateCompletionResponse struct {
-	Choices []CompletionChoice `json:"choices"`
+// TokenLogprob is the log probability of a generated token.
+// TopLogprobs are the most likely tokens at its position, see ChatCompletionRequest.TopLogprobs.
+type TokenLogprob struct {
+	Token       string       `json:"token"`
+	Logprob     float64      `json:"logprob"`
+	Bytes       []int        `json:"bytes"`
+	TopLogprobs []TopLogprob `json:"top_logprobs"`
+}
+
+// TopLogprob is one of the most likely tokens at the position of a TokenLogprob
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes"`
 }
 
 // Message is used to unmarshal OpenAI API response in the CreateCompletion function
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// Refusal is the explanation of the model when it refuses to answer
+	Refusal string `json:"refusal,omitempty"`
+	// ToolCalls are the functions the model asked to call, FinishReason is then FinishReasonToolCalls
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
-func (c ChatGPT) CreateCompletion(chatStory []message.Message) (string, error) {
-	const URL = "https://api.openai.com/v1/chat/completions"
-	headers := map[string]string{
-		"Content-Type":  "application/json",
-		"Authorization": "Bearer " + c.APIKey,
-	}
-	completionModel := c.Model
-	if c.Model == "" {
-		completionModel = DefaultModel
+// ToMessage converts the reply into a message.AssistantMessage to be appended to the chat story,
+// keeping its tool calls
+func (m Message) ToMessage() *message.AssistantMessage {
+	return message.NewAssistantToolCallMessage(m.Content, m.ToolCalls)
+}
+
+// Usage is the number of tokens billed for a ChatCompletion
+type Usage struct {
+	PromptTokens            int                     `json:"prompt_tokens"`
+	CompletionTokens        int                     `json:"completion_tokens"`
+	TotalTokens             int                     `json:"total_tokens"`
+	PromptTokensDetails     PromptTokensDetails     `json:"prompt_tokens_details"`
+	CompletionTokensDetails CompletionTokensDetails `json:"completion_tokens_details"`
+}
+
+// PromptTokensDetails breaks down the prompt tokens of Usage
+type PromptTokensDetails struct {
+	// CachedTokens were read from the prompt cache and are billed at a discount
+	CachedTokens int `json:"cached_tokens"`
+	AudioTokens  int `json:"audio_tokens"`
+}
+
+// CompletionTokensDetails breaks down the completion tokens of Usage
+type CompletionTokensDetails struct {
+	// ReasoningTokens were generated by a reasoning model but not returned in the message
+	ReasoningTokens          int `json:"reasoning_tokens"`
+	AudioTokens              int `json:"audio_tokens"`
+	AcceptedPredictionTokens int `json:"accepted_prediction_tokens"`
+	RejectedPredictionTokens int `json:"rejected_prediction_tokens"`
+}
+
+// Content returns the message content of the first choice or an empty string when there are no choices
+func (c ChatCompletion) Content() string {
+	if len(c.Choices) == 0 {
+		return ""
 	}
+	return c.Choices[0].Message.Content
+}
+
+func (c ChatGPT) CreateCompletion(chatStory []message.Message) (string, error) {
+	return c.CreateCompletionContext(context.Background(), chatStory)
+}
 
-	payload := CreateCompletionRequest{
-		Model:    completionModel,
+// CreateCompletionContext is like CreateCompletion but uses `ctx` to cancel the outgoing request.
+func (c ChatGPT) CreateCompletionContext(ctx context.Context, chatStory []message.Message) (string, error) {
+	completionResponse, err := c.CreateChatCompletion(ctx, ChatCompletionRequest{
 		Messages: chatStory,
-	}
-	requestBytes, err := json.Marshal(&payload)
+	})
 	if err != nil {
 		return "", err
 	}
+	if len(completionResponse.Choices) == 0 {
+		return "", fmt.Errorf("no response returned from chatgpt")
+	}
+	return completionResponse.Choices[0].Message.Content, nil
+}
 
-	request, err := http.NewRequest("POST", URL, bytes.NewBuffer(requestBytes))
-	if err != nil {
-		return "", err
+// CreateChatCompletion sends a chat completion request with all parameters of ChatCompletionRequest.
+// CreateCompletion is a shortcut for the common case of a chat story with default parameters.
+func (c ChatGPT) CreateChatCompletion(ctx context.Context, request ChatCompletionRequest) (ChatCompletion, error) {
+	if request.Model == "" {
+		request.Model = c.Model
 	}
-	for k, v := range headers {
-		request.Header.Set(k, v)
+	if request.Model == "" {
+		request.Model = DefaultModel
 	}
 
-	client := http.Client{}
-	response, err := client.Do(request)
-	responseBody, err := io.ReadAll(response.Body)
+	var completionResponse ChatCompletion
+	err := c.api().Call(ctx, transport.Request{
+		Endpoint: "chat.completions.create",
+		Method:   http.MethodPost,
+		Path:     "/chat/completions",
+		Model:    request.Model,
+		Body:     request,
+		// completions don't change any state on OpenAI side, so they are safe to retry
+		Retryable: true,
+	}, &completionResponse)
 	if err != nil {
-		return "", err
-	}
-	if response.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error creating completion %v %s", response.StatusCode, string(responseBody))
+		return ChatCompletion{}, err
 	}
+	return completionResponse, nil
+}
 
-	var completionResponse CreateCompletionResponse
-	if err = json.Unmarshal(responseBody, &completionResponse); err != nil {
-		return "", nil
-	}
-	if len(completionResponse.Choices) == 0 {
-		return "", fmt.Errorf("no response returned from chatgpt")
+// Create is the resource-style name of CreateCompletion
+func (c ChatGPT) Create(chatStory []message.Message) (string, error) {
+	return c.CreateCompletionContext(context.Background(), chatStory)
+}
+
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (c ChatGPT) CreateContext(ctx context.Context, chatStory []message.Message) (string, error) {
+	return c.CreateCompletionContext(ctx, chatStory)
+}
+
+func (c ChatGPT) api() *transport.Client {
+	if c.Transport != nil {
+		return c.Transport
 	}
-	return completionResponse.Choices[0].Message.Content, nil
+	return &transport.Client{APIKey: c.APIKey}
 }
@@ -0,0 +1,229 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Types of ContentPart
+const (
+	PartText       = "text"
+	PartImageURL   = "image_url"
+	PartInputAudio = "input_audio"
+	PartFile       = "file"
+)
+
+// Detail levels of image parts. Low detail images cost fewer tokens.
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+// Formats of audio parts
+const (
+	AudioFormatWAV = "wav"
+	AudioFormatMP3 = "mp3"
+)
+
+// ContentPart is a part of a multimodal UserMessage: text, an image, audio or a file.
+// Use the New...Part constructors and NewUserMessageParts.
+type ContentPart struct {
+	Type       string      `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	ImageURL   *ImageURL   `json:"image_url,omitempty"`
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+	File       *File       `json:"file,omitempty"`
+}
+
+// ImageURL is an image given by a URL or a base64 data URL
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// InputAudio is base64-encoded audio in the wav or mp3 format
+type InputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+// File is a file uploaded with the files API (FileID) or sent inline as a base64 data URL (FileData)
+type File struct {
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+}
+
+// NewUserMessageParts initializes a new UserMessage with multimodal content,
+// f.e. a question and an image:
+//
+//	image, err := message.NewImagePartFromFile("chart.png", message.ImageDetailHigh)
+//	...
+//	msg := message.NewUserMessageParts(message.NewTextPart("What does the chart show?"), image)
+func NewUserMessageParts(parts ...ContentPart) *UserMessage {
+	return &UserMessage{
+		Role:  "user",
+		Parts: parts,
+	}
+}
+
+// NewTextPart returns a text ContentPart
+func NewTextPart(text string) ContentPart {
+	return ContentPart{Type: PartText, Text: text}
+}
+
+// NewImageURLPart returns an image ContentPart of the image at `url`, which may be a base64 data URL.
+// `detail` is one of the ImageDetail constants, empty for the default.
+func NewImageURLPart(url, detail string) ContentPart {
+	return ContentPart{Type: PartImageURL, ImageURL: &ImageURL{URL: url, Detail: detail}}
+}
+
+// NewImagePartFromReader returns an image ContentPart sending the image read from `r` inline as a data URL.
+// The image type is detected from its content.
+func NewImagePartFromReader(r io.Reader, detail string) (ContentPart, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ContentPart{}, fmt.Errorf("unsupported image type %q", mimeType)
+	}
+	return NewImageURLPart(dataURL(mimeType, data), detail), nil
+}
+
+// NewImagePartFromFile is like NewImagePartFromReader but reads the image from the file at `path`
+func NewImagePartFromFile(path, detail string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	defer f.Close()
+	return NewImagePartFromReader(f, detail)
+}
+
+// NewAudioPartFromReader returns an audio ContentPart of the audio read from `r`.
+// `format` is one of the AudioFormat constants or empty to detect it from the content.
+func NewAudioPartFromReader(r io.Reader, format string) (ContentPart, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read audio: %w", err)
+	}
+	if format == "" {
+		switch mimeType := http.DetectContentType(data); mimeType {
+		case "audio/wave":
+			format = AudioFormatWAV
+		case "audio/mpeg":
+			format = AudioFormatMP3
+		default:
+			return ContentPart{}, fmt.Errorf("unsupported audio type %q", mimeType)
+		}
+	}
+	return ContentPart{Type: PartInputAudio, InputAudio: &InputAudio{
+		Data:   base64.StdEncoding.EncodeToString(data),
+		Format: format,
+	}}, nil
+}
+
+// NewAudioPartFromFile is like NewAudioPartFromReader but reads the audio from the file at `path`.
+// The format is taken from the .wav or .mp3 extension when the content doesn't tell.
+func NewAudioPartFromFile(path string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	part, err := NewAudioPartFromReader(bytes.NewReader(data), "")
+	if err == nil {
+		return part, nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return NewAudioPartFromReader(bytes.NewReader(data), AudioFormatWAV)
+	case ".mp3":
+		return NewAudioPartFromReader(bytes.NewReader(data), AudioFormatMP3)
+	}
+	return ContentPart{}, err
+}
+
+// NewFilePart returns a file ContentPart of the file with `fileID` uploaded with the files API
+func NewFilePart(fileID string) ContentPart {
+	return ContentPart{Type: PartFile, File: &File{FileID: fileID}}
+}
+
+// NewFilePartFromReader returns a file ContentPart sending the file read from `r` inline as a data URL.
+// The file type is taken from the extension of `filename` or detected from the content.
+func NewFilePartFromReader(filename string, r io.Reader) (ContentPart, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	mimeType := mime.TypeByExtension(filepath.Ext(filename))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return ContentPart{Type: PartFile, File: &File{
+		Filename: filename,
+		FileData: dataURL(mimeType, data),
+	}}, nil
+}
+
+// NewFilePartFromFile is like NewFilePartFromReader but reads the file at `path`
+func NewFilePartFromFile(path string) (ContentPart, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+	defer f.Close()
+	return NewFilePartFromReader(filepath.Base(path), f)
+}
+
+// dataURL encodes `data` as a base64 data URL, dropping the parameters of `mimeType` (f.e. charset)
+func dataURL(mimeType string, data []byte) string {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// MarshalJSON sends Content as a string, or the content parts as an array when Parts are set.
+// A non-empty Content is then sent as the first text part.
+func (um UserMessage) MarshalJSON() ([]byte, error) {
+	type userMessage UserMessage
+	if len(um.Parts) == 0 {
+		return json.Marshal(userMessage(um))
+	}
+	parts := um.Parts
+	if um.Content != "" {
+		parts = append([]ContentPart{NewTextPart(um.Content)}, parts...)
+	}
+	return json.Marshal(struct {
+		Role    string        `json:"role"`
+		Content []ContentPart `json:"content"`
+	}{um.Role, parts})
+}
+
+// UnmarshalJSON decodes a message with a string content or an array of content parts into Parts
+func (um *UserMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*um = UserMessage{Role: raw.Role}
+	if len(raw.Content) > 0 && raw.Content[0] == '[' {
+		return json.Unmarshal(raw.Content, &um.Parts)
+	}
+	if len(raw.Content) > 0 && string(raw.Content) != "null" {
+		return json.Unmarshal(raw.Content, &um.Content)
+	}
+	return nil
+}
@@ -1,31 +1,56 @@
 package message
 
+import "strings"
+
 // Message represents a message object in a ChatGPT chatStory
 // You need to pass full chat story in your request to `Create a Completion`
 // in order to get a response from ChatGPT.
-// Message has 3 implementations: UserMessage, SystemMessage and AssistantMessage.
+// Message has 4 implementations: UserMessage, SystemMessage, AssistantMessage and ToolMessage.
 type Message interface {
 	Message() string
 }
 
 type (
-	// UserMessage is used to represent a user message in a chat with ChatGPT
+	// UserMessage is used to represent a user message in a chat with ChatGPT.
+	// Parts hold multimodal content like images, see NewUserMessageParts.
 	UserMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
+		Role    string        `json:"role"`
+		Content string        `json:"content"`
+		Parts   []ContentPart `json:"-"`
 	}
 	// SystemMessage is used to represent a system instructions to ChatGPT in a chat with it.
 	SystemMessage struct {
 		Role    string `json:"role"`
 		Content string `json:"content"`
 	}
-	// AssistantMessage represents ChatGPT response
+	// AssistantMessage represents ChatGPT response.
+	// ToolCalls are set when the model asked to call functions instead of answering.
 	AssistantMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
+		Role      string     `json:"role"`
+		Content   string     `json:"content"`
+		ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	}
+	// ToolMessage sends the result of a tool call back to ChatGPT
+	ToolMessage struct {
+		Role       string `json:"role"`
+		Content    string `json:"content"`
+		ToolCallID string `json:"tool_call_id"`
 	}
 )
 
+// ToolCall is a function call requested by ChatGPT in an assistant message
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall is the function name and its JSON-encoded arguments of a ToolCall
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
 // NewUserMessage initializes a new UserMessage object with content
 func NewUserMessage(content string) *UserMessage {
 	return &UserMessage{
@@ -36,8 +61,18 @@ func NewUserMessage(content string) *UserMessage {
 
 // Message is a getter function to get a message content.
 // Is needed to implement the Message interface via Go duck typing.
+// Text parts are joined into the content of multimodal messages.
 func (um *UserMessage) Message() string {
-	return um.Content
+	texts := make([]string, 0, len(um.Parts)+1)
+	if um.Content != "" {
+		texts = append(texts, um.Content)
+	}
+	for _, part := range um.Parts {
+		if part.Type == PartText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
 }
 
 // NewSystemMessage initializes a new SystemMessage object with content
@@ -67,3 +102,28 @@ func NewAssistantMessage(content string) *AssistantMessage {
 func (am *AssistantMessage) Message() string {
 	return am.Content
 }
+
+// NewAssistantToolCallMessage initializes a new AssistantMessage with the tool calls requested by ChatGPT,
+// which must precede the ToolMessages with their results in a chat story
+func NewAssistantToolCallMessage(content string, toolCalls []ToolCall) *AssistantMessage {
+	return &AssistantMessage{
+		Role:      "assistant",
+		Content:   content,
+		ToolCalls: toolCalls,
+	}
+}
+
+// NewToolMessage initializes a new ToolMessage with the result `content` of the tool call with `toolCallID`
+func NewToolMessage(toolCallID, content string) *ToolMessage {
+	return &ToolMessage{
+		Role:       "tool",
+		Content:    content,
+		ToolCallID: toolCallID,
+	}
+}
+
+// Message is a getter function to get a message content.
+// Is needed to implement the Message interface via Go duck typing.
+func (tm *ToolMessage) Message() string {
+	return tm.Content
+}
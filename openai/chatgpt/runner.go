@@ -0,0 +1,171 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/schema"
+	"sync"
+)
+
+// DefaultMaxSteps is the number of chat completions a ToolRunner makes per conversation unless MaxSteps is set
+const DefaultMaxSteps = 10
+
+// ErrMaxSteps is returned by RunConversation when the model still calls tools after MaxSteps completions
+var ErrMaxSteps = errors.New("chatgpt: tool runner reached the max steps limit")
+
+// ToolRunner answers a conversation letting the model call Go functions registered with RegisterTool:
+//
+//	runner := chatgpt.NewToolRunner(client)
+//	chatgpt.RegisterTool(runner, "get_weather", "Returns the weather in a city", nil,
+//		func(ctx context.Context, args WeatherArgs) (any, error) {
+//			return weather.Current(ctx, args.City)
+//		})
+//	transcript, err := runner.RunConversation(ctx, history)
+type ToolRunner struct {
+	Client ChatCompleter
+	// Request is the template of every chat completion request (f.e. Model or Temperature).
+	// Its Messages and Tools are replaced by the conversation and the registered tools.
+	Request ChatCompletionRequest
+	// MaxSteps limits the number of chat completions per conversation, DefaultMaxSteps when zero
+	MaxSteps int
+
+	tools []registeredTool
+}
+
+type registeredTool struct {
+	tool Tool
+	call func(ctx context.Context, arguments string) (any, error)
+}
+
+// NewToolRunner returns a ToolRunner sending chat completions with `client`
+func NewToolRunner(client ChatCompleter) *ToolRunner {
+	return &ToolRunner{Client: client}
+}
+
+// RegisterTool makes `handler` callable by the model as the function `name`. The JSON arguments of a call
+// are decoded into T, which should match the JSON Schema `parameters`. When `parameters` is nil the schema
+// is generated from T (see the schema package), so it panics if T can't be described with JSON Schema.
+// The result of the handler is sent back to the model as is when it is a string and JSON-encoded otherwise.
+// Registering a name again replaces the tool.
+func RegisterTool[T any](r *ToolRunner, name, description string, parameters any, handler func(ctx context.Context, args T) (any, error)) {
+	if parameters == nil {
+		s, err := schema.For[T]()
+		if err != nil {
+			panic(fmt.Sprintf("chatgpt: parameters of tool %q: %v", name, err))
+		}
+		parameters = s
+	}
+	registered := registeredTool{
+		tool: NewFunctionTool(name, description, parameters),
+		call: func(ctx context.Context, arguments string) (any, error) {
+			var args T
+			if arguments != "" {
+				if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+					return nil, fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			return handler(ctx, args)
+		},
+	}
+	for i, t := range r.tools {
+		if t.tool.Function.Name == name {
+			r.tools[i] = registered
+			return
+		}
+	}
+	r.tools = append(r.tools, registered)
+}
+
+// RunConversation sends `history` with the registered tools, executes the requested tool calls
+// (concurrently when the model requests several at once), appends their results and repeats
+// until the model answers without calling tools. It returns the full transcript: `history`
+// followed by the assistant tool calls, the tool results and the final assistant answer.
+// Failed tool calls are reported to the model instead of stopping the conversation.
+func (r *ToolRunner) RunConversation(ctx context.Context, history []message.Message) ([]message.Message, error) {
+	transcript := append([]message.Message(nil), history...)
+	tools := make([]Tool, 0, len(r.tools))
+	for _, t := range r.tools {
+		tools = append(tools, t.tool)
+	}
+	maxSteps := r.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+
+	for step := 0; step < maxSteps; step++ {
+		request := r.Request
+		request.Messages = transcript
+		request.Tools = tools
+		completion, err := r.Client.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return transcript, err
+		}
+		if len(completion.Choices) == 0 {
+			return transcript, fmt.Errorf("no response returned from chatgpt")
+		}
+
+		reply := completion.Choices[0].Message
+		transcript = append(transcript, reply.ToMessage())
+		if len(reply.ToolCalls) == 0 {
+			return transcript, nil
+		}
+		for _, result := range r.callTools(ctx, reply.ToolCalls) {
+			transcript = append(transcript, result)
+		}
+	}
+	return transcript, ErrMaxSteps
+}
+
+// callTools executes `calls` concurrently and returns their results in the order of the calls
+func (r *ToolRunner) callTools(ctx context.Context, calls []ToolCall) []*message.ToolMessage {
+	results := make([]*message.ToolMessage, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = message.NewToolMessage(call.ID, r.callTool(ctx, call))
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *ToolRunner) callTool(ctx context.Context, call ToolCall) (content string) {
+	defer func() {
+		if p := recover(); p != nil {
+			content = toolError(fmt.Errorf("panic: %v", p))
+		}
+	}()
+
+	var registered *registeredTool
+	for i := range r.tools {
+		if r.tools[i].tool.Function.Name == call.Function.Name {
+			registered = &r.tools[i]
+		}
+	}
+	if registered == nil {
+		return toolError(fmt.Errorf("unknown tool %q", call.Function.Name))
+	}
+	result, err := registered.call(ctx, call.Function.Arguments)
+	if err != nil {
+		return toolError(err)
+	}
+	if s, ok := result.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return toolError(fmt.Errorf("failed to encode result: %w", err))
+	}
+	return string(data)
+}
+
+// toolError is the tool result telling the model that the call failed
+func toolError(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
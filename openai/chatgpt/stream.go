@@ -0,0 +1,249 @@
+package chatgpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"io"
+	"net/http"
+)
+
+// ChatCompletionChunk is a part of a streamed chat completion.
+// See https://platform.openai.com/docs/api-reference/chat-streaming/streaming
+type ChatCompletionChunk struct {
+	ID                string        `json:"id"`
+	Object            string        `json:"object"`
+	Created           int64         `json:"created"`
+	Model             string        `json:"model"`
+	SystemFingerprint string        `json:"system_fingerprint"`
+	ServiceTier       string        `json:"service_tier"`
+	Choices           []ChunkChoice `json:"choices"`
+	// Usage is set only in the last chunk when StreamOptions.IncludeUsage is enabled
+	Usage *Usage `json:"usage"`
+}
+
+// ChunkChoice is the delta of one of the choices in a ChatCompletionChunk
+type ChunkChoice struct {
+	Index        int    `json:"index"`
+	Delta        Delta  `json:"delta"`
+	FinishReason string `json:"finish_reason"`
+	// Logprobs are the log probabilities of the tokens of this chunk
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+}
+
+// Delta is the part of a message generated since the previous chunk
+type Delta struct {
+	Role      string          `json:"role"`
+	Content   string          `json:"content"`
+	Refusal   string          `json:"refusal"`
+	ToolCalls []ToolCallDelta `json:"tool_calls"`
+}
+
+// ToolCallDelta is a fragment of a tool call. The first fragment of a call carries its ID, type and
+// function name, the following ones with the same Index carry pieces of the function arguments.
+type ToolCallDelta struct {
+	Index    int               `json:"index"`
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Function FunctionCallDelta `json:"function"`
+}
+
+// FunctionCallDelta is the function part of a ToolCallDelta
+type FunctionCallDelta struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletionStream reads the chunks of a streamed chat completion:
+//
+//	stream, err := client.CreateChatCompletionStream(ctx, request)
+//	if err != nil {
+//		return err
+//	}
+//	defer stream.Close()
+//	for stream.Next() {
+//		chunk := stream.Current()
+//		// the last chunk has no choices when usage is requested
+//		if len(chunk.Choices) > 0 {
+//			fmt.Print(chunk.Choices[0].Delta.Content)
+//		}
+//	}
+//	return stream.Err()
+type ChatCompletionStream struct {
+	resp    *http.Response
+	events  *transport.EventReader
+	current ChatCompletionChunk
+	err     error
+	done    bool
+}
+
+// CreateChatCompletionStream sends the chat completion request with `stream: true` and returns the stream
+// of its chunks as soon as the response headers arrive. Unless request.StreamOptions is set,
+// usage is requested in the last chunk. Note that WithTimeout limits the time of the whole stream.
+func (c ChatGPT) CreateChatCompletionStream(ctx context.Context, request ChatCompletionRequest) (*ChatCompletionStream, error) {
+	if request.Model == "" {
+		request.Model = c.Model
+	}
+	if request.Model == "" {
+		request.Model = DefaultModel
+	}
+	request.Stream = true
+	if request.StreamOptions == nil {
+		request.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+
+	resp, err := c.api().Stream(ctx, transport.Request{
+		Endpoint:  "chat.completions.create",
+		Method:    http.MethodPost,
+		Path:      "/chat/completions",
+		Model:     request.Model,
+		Body:      request,
+		Retryable: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ChatCompletionStream{
+		resp:   resp,
+		events: transport.NewEventReader(resp.Body),
+	}, nil
+}
+
+// Next advances the stream to the next chunk, which is then available through Current.
+// It returns false when the stream ends or fails; check Err to tell the two apart.
+func (s *ChatCompletionStream) Next() bool {
+	if s.done {
+		return false
+	}
+	for {
+		event, err := s.events.Next()
+		if err != nil {
+			if err != io.EOF {
+				s.err = fmt.Errorf("failed to read chat completion stream: %w", err)
+			}
+			s.done = true
+			return false
+		}
+		data := bytes.TrimSpace(event.Data)
+		if len(data) == 0 {
+			continue
+		}
+		if string(data) == "[DONE]" {
+			s.done = true
+			return false
+		}
+		if event.Name == "error" || bytes.HasPrefix(data, []byte(`{"error"`)) {
+			s.err = transport.NewAPIError(s.resp, data)
+			s.done = true
+			return false
+		}
+
+		var chunk ChatCompletionChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			s.err = fmt.Errorf("failed to decode chat completion chunk: %w", err)
+			s.done = true
+			return false
+		}
+		s.current = chunk
+		return true
+	}
+}
+
+// Current returns the chunk read by the last call of Next
+func (s *ChatCompletionStream) Current() ChatCompletionChunk {
+	return s.current
+}
+
+// Err returns the error that stopped the stream, nil if it ended normally
+func (s *ChatCompletionStream) Err() error {
+	return s.err
+}
+
+// Close closes the underlying response body. Closing the stream before its end cancels the generation.
+func (s *ChatCompletionStream) Close() error {
+	s.done = true
+	return s.resp.Body.Close()
+}
+
+// Accumulate reads the rest of the stream, closes it and returns the chunks combined into a ChatCompletion
+func (s *ChatCompletionStream) Accumulate() (ChatCompletion, error) {
+	defer s.Close()
+	var acc ChatCompletionAccumulator
+	for s.Next() {
+		acc.Add(s.Current())
+	}
+	return acc.ChatCompletion(), s.Err()
+}
+
+// ChatCompletionAccumulator combines ChatCompletionChunks into the ChatCompletion
+// that would be returned without streaming. The zero value is ready to use.
+type ChatCompletionAccumulator struct {
+	completion ChatCompletion
+}
+
+// Add merges `chunk` into the accumulated completion
+func (a *ChatCompletionAccumulator) Add(chunk ChatCompletionChunk) {
+	c := &a.completion
+	if c.ID == "" {
+		c.ID = chunk.ID
+		c.Object = "chat.completion"
+		c.Created = chunk.Created
+		c.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		c.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.ServiceTier != "" {
+		c.ServiceTier = chunk.ServiceTier
+	}
+	if chunk.Usage != nil {
+		c.Usage = *chunk.Usage
+	}
+	for _, delta := range chunk.Choices {
+		for len(c.Choices) <= delta.Index {
+			c.Choices = append(c.Choices, CompletionChoice{Index: len(c.Choices)})
+		}
+		choice := &c.Choices[delta.Index]
+		if delta.Delta.Role != "" {
+			choice.Role = delta.Delta.Role
+		}
+		choice.Content += delta.Delta.Content
+		choice.Refusal += delta.Delta.Refusal
+		if delta.Logprobs != nil {
+			if choice.Logprobs == nil {
+				choice.Logprobs = &Logprobs{}
+			}
+			choice.Logprobs.Content = append(choice.Logprobs.Content, delta.Logprobs.Content...)
+			choice.Logprobs.Refusal = append(choice.Logprobs.Refusal, delta.Logprobs.Refusal...)
+		}
+		for _, call := range delta.Delta.ToolCalls {
+			for len(choice.ToolCalls) <= call.Index {
+				choice.ToolCalls = append(choice.ToolCalls, ToolCall{})
+			}
+			toolCall := &choice.ToolCalls[call.Index]
+			if call.ID != "" {
+				toolCall.ID = call.ID
+			}
+			if call.Type != "" {
+				toolCall.Type = call.Type
+			}
+			toolCall.Function.Name += call.Function.Name
+			toolCall.Function.Arguments += call.Function.Arguments
+		}
+		if delta.FinishReason != "" {
+			choice.FinishReason = delta.FinishReason
+		}
+	}
+}
+
+// ChatCompletion returns the completion accumulated so far
+func (a *ChatCompletionAccumulator) ChatCompletion() ChatCompletion {
+	completion := a.completion
+	completion.Choices = append([]CompletionChoice(nil), a.completion.Choices...)
+	for i := range completion.Choices {
+		completion.Choices[i].ToolCalls = append([]ToolCall(nil), completion.Choices[i].ToolCalls...)
+	}
+	return completion
+}
@@ -0,0 +1,229 @@
+package chatgpt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/schema"
+	"reflect"
+	"regexp"
+)
+
+// Types of ResponseFormat
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat sets the format of the model replies, see JSONSchemaFormat and JSONObjectFormat
+type ResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaFormat is the schema replies follow with the "json_schema" ResponseFormat
+type JSONSchemaFormat struct {
+	// Name may contain letters, digits, underscores and dashes
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      any    `json:"schema"`
+	// Strict makes the model follow Schema exactly; the schema must then meet the strict mode requirements
+	Strict bool `json:"strict,omitempty"`
+}
+
+// NewJSONSchemaFormat returns the "json_schema" ResponseFormat named `name`.
+// Strict is set when `s` meets the strict mode requirements.
+func NewJSONSchemaFormat(name string, s *schema.Schema) *ResponseFormat {
+	return &ResponseFormat{
+		Type: ResponseFormatJSONSchema,
+		JSONSchema: &JSONSchemaFormat{
+			Name:   name,
+			Schema: s,
+			Strict: s.Strict(),
+		},
+	}
+}
+
+// NewJSONObjectFormat returns the "json_object" ResponseFormat (JSON mode). The model replies with
+// any valid JSON object, so the messages must ask for JSON and describe its shape.
+func NewJSONObjectFormat() *ResponseFormat {
+	return &ResponseFormat{Type: ResponseFormatJSONObject}
+}
+
+// RefusalError is returned by CreateStructured when the model refuses to answer, f.e. for safety reasons
+type RefusalError struct {
+	Refusal string
+}
+
+func (e *RefusalError) Error() string {
+	return "chatgpt: model refused to answer: " + e.Refusal
+}
+
+// DecodeError is returned by CreateStructured when the reply doesn't decode into the requested type
+type DecodeError struct {
+	// Content is the reply of the model
+	Content string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return "chatgpt: failed to decode structured output: " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// StructuredOption configures CreateStructured
+type StructuredOption func(*structuredOptions)
+
+type structuredOptions struct {
+	request  ChatCompletionRequest
+	name     string
+	jsonMode bool
+	reprompt bool
+}
+
+// WithRequest sets the template of the chat completion request (f.e. Model or Temperature).
+// Its Messages and ResponseFormat are replaced.
+func WithRequest(request ChatCompletionRequest) StructuredOption {
+	return func(o *structuredOptions) {
+		o.request = request
+	}
+}
+
+// WithSchemaName sets the name of the JSON Schema, the name of the Go type by default
+func WithSchemaName(name string) StructuredOption {
+	return func(o *structuredOptions) {
+		o.name = name
+	}
+}
+
+// WithJSONMode uses the "json_object" ResponseFormat instead of a strict JSON Schema, f.e. for models
+// not supporting structured outputs. The schema is then sent to the model in a system message,
+// and the fields of the reply T doesn't have are ignored.
+func WithJSONMode() StructuredOption {
+	return func(o *structuredOptions) {
+		o.jsonMode = true
+	}
+}
+
+// WithReprompt makes CreateStructured send the decoding error back to the model and ask it
+// to answer again once when the reply doesn't decode into the requested type
+func WithReprompt() StructuredOption {
+	return func(o *structuredOptions) {
+		o.reprompt = true
+	}
+}
+
+// CreateStructured answers `history` with a value of type T. The model is asked to reply with JSON
+// following the JSON Schema generated from T (see the schema package), which is decoded into T.
+// Structured outputs need an object at the root of the schema, so a T described by another
+// JSON type (f.e. a slice or a pointer) is requested as the "value" property of an object.
+// A refusal of the model is returned as *RefusalError and a reply not matching T as *DecodeError.
+func CreateStructured[T any](ctx context.Context, client ChatCompleter, history []message.Message, opts ...StructuredOption) (T, error) {
+	var zero T
+	var o structuredOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s, err := schema.For[T]()
+	if err != nil {
+		return zero, err
+	}
+	wrapped := s.Type != "object"
+	if wrapped {
+		if s, err = schema.For[valueObject[T]](); err != nil {
+			return zero, err
+		}
+	}
+	// unknown fields are rejected only when the model must follow the schema exactly
+	strict := !o.jsonMode && s.Strict()
+	if o.name == "" {
+		o.name = schemaName(reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	request := o.request
+	request.Messages = append([]message.Message(nil), history...)
+	if o.jsonMode {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return zero, err
+		}
+		request.ResponseFormat = NewJSONObjectFormat()
+		request.Messages = append(request.Messages, message.NewSystemMessage(
+			"Reply with a JSON object following this JSON Schema: "+string(data)))
+	} else {
+		request.ResponseFormat = NewJSONSchemaFormat(o.name, s)
+	}
+
+	for attempt := 0; ; attempt++ {
+		completion, err := client.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return zero, err
+		}
+		if len(completion.Choices) == 0 {
+			return zero, fmt.Errorf("no response returned from chatgpt")
+		}
+		reply := completion.Choices[0].Message
+		if reply.Refusal != "" {
+			return zero, &RefusalError{Refusal: reply.Refusal}
+		}
+
+		value, err := decodeReply[T](reply.Content, wrapped, strict)
+		if err == nil {
+			return value, nil
+		}
+		if !o.reprompt || attempt > 0 {
+			return zero, &DecodeError{Content: reply.Content, Err: err}
+		}
+		request.Messages = append(request.Messages, reply.ToMessage(), message.NewUserMessage(fmt.Sprintf(
+			"Your reply could not be decoded: %v. Reply again with JSON following the schema.", err)))
+	}
+}
+
+// valueObject is the root object of the schema of a T which isn't described by a JSON object
+type valueObject[T any] struct {
+	Value T `json:"value"`
+}
+
+// decodeReply decodes the JSON `content` into a T, which is the "value" property of `content` when `wrapped`
+func decodeReply[T any](content string, wrapped, strict bool) (T, error) {
+	if !wrapped {
+		var value T
+		err := decodeJSON(content, &value, strict)
+		return value, err
+	}
+	var object valueObject[T]
+	err := decodeJSON(content, &object, strict)
+	return object.Value, err
+}
+
+// decodeJSON decodes the JSON `content` into `v`, rejecting trailing data and, when `strict`, unknown fields
+func decodeJSON(content string, v any, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(content)))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// schemaName returns the name of `t` with the characters not allowed in JSON Schema names replaced
+func schemaName(t reflect.Type) string {
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package chatgpt
+
+import (
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/schema"
+)
+
+// Modes of ToolChoice
+const (
+	ToolChoiceNone     = "none"
+	ToolChoiceAuto     = "auto"
+	ToolChoiceRequired = "required"
+)
+
+type (
+	// ToolCall is a function call requested by ChatGPT, see message.ToolCall
+	ToolCall = message.ToolCall
+	// FunctionCall is the function part of a ToolCall, see message.FunctionCall
+	FunctionCall = message.FunctionCall
+)
+
+// Tool is a tool the model may call, currently always a function
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionDefinition describes a function the model may call
+type FunctionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON Schema of the function arguments object,
+	// f.e. a *schema.Schema, a map[string]any or a json.RawMessage
+	Parameters any `json:"parameters,omitempty"`
+	// Strict makes the model follow Parameters exactly; the schema must then meet the strict mode requirements
+	Strict bool `json:"strict,omitempty"`
+}
+
+// NewFunctionTool returns a function Tool with the JSON Schema `parameters` of its arguments.
+// Strict is set when `parameters` is a *schema.Schema meeting the strict mode requirements.
+func NewFunctionTool(name, description string, parameters any) Tool {
+	s, ok := parameters.(*schema.Schema)
+	return Tool{
+		Type: "function",
+		Function: FunctionDefinition{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+			Strict:      ok && s != nil && s.Strict(),
+		},
+	}
+}
+
+// ToolChoice controls whether and which tool the model calls.
+// Use ToolChoiceMode for "none", "auto" and "required" or ToolChoiceFunction to force a function.
+type ToolChoice struct {
+	Mode     string
+	Function string
+}
+
+// ToolChoiceMode returns a ToolChoice with one of ToolChoiceNone, ToolChoiceAuto or ToolChoiceRequired
+func ToolChoiceMode(mode string) *ToolChoice {
+	return &ToolChoice{Mode: mode}
+}
+
+// ToolChoiceFunction returns a ToolChoice forcing the model to call the function `name`
+func ToolChoiceFunction(name string) *ToolChoice {
+	return &ToolChoice{Function: name}
+}
+
+// MarshalJSON encodes the choice as a mode string or a `{"type": "function", ...}` object
+func (t ToolChoice) MarshalJSON() ([]byte, error) {
+	if t.Function == "" {
+		return json.Marshal(t.Mode)
+	}
+	return json.Marshal(map[string]any{
+		"type":     "function",
+		"function": map[string]string{"name": t.Function},
+	})
+}
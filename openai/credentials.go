@@ -0,0 +1,192 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialProvider provides the API key for every request. See WithCredentials.
+type CredentialProvider = transport.Credentials
+
+// StaticKey returns a provider always returning `apiKey`
+func StaticKey(apiKey string) CredentialProvider {
+	return staticKey(apiKey)
+}
+
+type staticKey string
+
+func (k staticKey) APIKey(context.Context) (string, error) {
+	return string(k), nil
+}
+
+// EnvKey returns a provider reading the API key from the environment variable `name` on every request
+func EnvKey(name string) CredentialProvider {
+	return envKey(name)
+}
+
+type envKey string
+
+func (k envKey) APIKey(context.Context) (string, error) {
+	apiKey := os.Getenv(string(k))
+	if apiKey == "" {
+		return "", fmt.Errorf("environment variable %s is empty", string(k))
+	}
+	return apiKey, nil
+}
+
+// FileKey returns a provider reading the API key from the file at `path`.
+// The file is read again whenever its modification time changes, so the key may be rotated in place.
+func FileKey(path string) CredentialProvider {
+	return &fileKey{path: path}
+}
+
+type fileKey struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	apiKey  string
+}
+
+func (k *fileKey) APIKey(context.Context) (string, error) {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return "", err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.apiKey != "" && info.ModTime().Equal(k.modTime) {
+		return k.apiKey, nil
+	}
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return "", err
+	}
+	apiKey := strings.TrimSpace(string(data))
+	if apiKey == "" {
+		return "", fmt.Errorf("api key file %s is empty", k.path)
+	}
+	k.apiKey, k.modTime = apiKey, info.ModTime()
+	return apiKey, nil
+}
+
+// CommandKey returns a provider taking the API key from the output of the command `name` with `args`
+// (f.e. a secret manager CLI). The output is cached for `ttl`; zero ttl runs the command only once.
+func CommandKey(ttl time.Duration, name string, args ...string) CredentialProvider {
+	return &commandKey{
+		ttl:  ttl,
+		name: name,
+		args: args,
+	}
+}
+
+type commandKey struct {
+	ttl       time.Duration
+	name      string
+	args      []string
+	mu        sync.Mutex
+	apiKey    string
+	expiresAt time.Time
+}
+
+func (k *commandKey) APIKey(ctx context.Context) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.apiKey != "" && (k.ttl == 0 || time.Now().Before(k.expiresAt)) {
+		return k.apiKey, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, k.name, k.args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("api key command %s failed: %w %s", k.name, err, strings.TrimSpace(stderr.String()))
+	}
+	apiKey := strings.TrimSpace(string(output))
+	if apiKey == "" {
+		return "", fmt.Errorf("api key command %s returned empty output", k.name)
+	}
+	k.apiKey, k.expiresAt = apiKey, time.Now().Add(k.ttl)
+	return apiKey, nil
+}
+
+// KeyPool spreads requests between several API keys in round-robin order.
+// A key rejected with 401 Unauthorized or 429 Too Many Requests is put on cooldown
+// and the request is sent again with the next available key.
+type KeyPool struct {
+	// Cooldown is the time a failed key is skipped for. One minute is used when it is zero.
+	Cooldown time.Duration
+
+	mu          sync.Mutex
+	keys        []string
+	next        int
+	failedUntil map[string]time.Time
+}
+
+// NewKeyPool creates a KeyPool of `apiKeys`
+func NewKeyPool(apiKeys ...string) *KeyPool {
+	return &KeyPool{
+		keys:        apiKeys,
+		failedUntil: make(map[string]time.Time),
+	}
+}
+
+// APIKey returns the next key which is not on cooldown.
+// When all keys have failed, the one recovering first is returned.
+func (p *KeyPool) APIKey(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return "", fmt.Errorf("key pool is empty")
+	}
+
+	now := time.Now()
+	best := -1
+	for i := range p.keys {
+		idx := (p.next + i) % len(p.keys)
+		if p.failedUntil[p.keys[idx]].Before(now) {
+			best = idx
+			break
+		}
+		if best == -1 || p.failedUntil[p.keys[idx]].Before(p.failedUntil[p.keys[best]]) {
+			best = idx
+		}
+	}
+	p.next = (best + 1) % len(p.keys)
+	return p.keys[best], nil
+}
+
+// Failed puts `apiKey` on cooldown and reports whether another key is available
+func (p *KeyPool) Failed(apiKey string, statusCode int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cooldown := p.Cooldown
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	if p.failedUntil == nil {
+		p.failedUntil = make(map[string]time.Time)
+	}
+	if statusCode == http.StatusUnauthorized {
+		// a revoked key won't recover soon
+		cooldown *= 10
+	}
+	now := time.Now()
+	p.failedUntil[apiKey] = now.Add(cooldown)
+	for _, key := range p.keys {
+		if p.failedUntil[key].Before(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package openai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by NewFromEnv
+const (
+	EnvAPIKey     = "OPENAI_API_KEY"
+	EnvBaseURL    = "OPENAI_BASE_URL"
+	EnvOrgID      = "OPENAI_ORG_ID"
+	EnvProjectID  = "OPENAI_PROJECT_ID"
+	EnvTimeout    = "OPENAI_TIMEOUT"
+	EnvProfile    = "OPENAI_PROFILE"
+	EnvConfigFile = "OPENAI_CONFIG_FILE"
+)
+
+// Profile is a named set of client settings stored in a config file, f.e. one per environment:
+//
+//	{
+//	  "profiles": {
+//	    "staging": {"api_key": "sk-...", "project": "proj_staging", "timeout": "30s"},
+//	    "local": {"api_key": "sk-local", "base_url": "http://localhost:8080/v1"}
+//	  }
+//	}
+type Profile struct {
+	APIKey       string
+	BaseURL      string
+	Organization string
+	Project      string
+	Timeout      time.Duration
+}
+
+type profileFile struct {
+	Profiles map[string]struct {
+		APIKey       string `json:"api_key"`
+		BaseURL      string `json:"base_url"`
+		Organization string `json:"organization"`
+		Project      string `json:"project"`
+		Timeout      string `json:"timeout"`
+	} `json:"profiles"`
+}
+
+// DefaultConfigFile returns the config file read by NewFromEnv when OPENAI_CONFIG_FILE is not set,
+// f.e. "~/.config/openai-go/config.json" on Linux
+func DefaultConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "openai-go", "config.json"), nil
+}
+
+// LoadProfile reads the profile `name` from the JSON config file at `path`
+func LoadProfile(path, name string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("openai: read config file: %w", err)
+	}
+	var file profileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Profile{}, fmt.Errorf("openai: decode config file %s: %w", path, err)
+	}
+	raw, ok := file.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("openai: profile %q not found in %s", name, path)
+	}
+	profile := Profile{
+		APIKey:       raw.APIKey,
+		BaseURL:      raw.BaseURL,
+		Organization: raw.Organization,
+		Project:      raw.Project,
+	}
+	if raw.Timeout != "" {
+		profile.Timeout, err = parseTimeout(raw.Timeout)
+		if err != nil {
+			return Profile{}, fmt.Errorf("openai: profile %q: invalid timeout %q: %w", name, raw.Timeout, err)
+		}
+	}
+	return profile, nil
+}
+
+// WithProfile applies the non-empty settings of `profile`
+func WithProfile(profile Profile) Option {
+	return func(o *options) {
+		if profile.APIKey != "" {
+			o.apiKey = profile.APIKey
+		}
+		if profile.BaseURL != "" {
+			o.baseURL = profile.BaseURL
+		}
+		if profile.Organization != "" {
+			o.organization = profile.Organization
+		}
+		if profile.Project != "" {
+			o.project = profile.Project
+		}
+		if profile.Timeout != 0 {
+			o.timeout = profile.Timeout
+		}
+	}
+}
+
+// NewFromEnv initializes a new OpenAI instance configured by the OPENAI_API_KEY, OPENAI_BASE_URL,
+// OPENAI_ORG_ID, OPENAI_PROJECT_ID and OPENAI_TIMEOUT (f.e. "30s" or a number of seconds) environment variables.
+// When OPENAI_PROFILE is set, the profile is loaded first from OPENAI_CONFIG_FILE or DefaultConfigFile.
+// Environment variables override the profile and `opts` override both.
+func NewFromEnv(opts ...Option) (*OpenAI, error) {
+	var envOpts []Option
+	if name := os.Getenv(EnvProfile); name != "" {
+		path := os.Getenv(EnvConfigFile)
+		if path == "" {
+			var err error
+			if path, err = DefaultConfigFile(); err != nil {
+				return nil, fmt.Errorf("openai: locate config file: %w", err)
+			}
+		}
+		profile, err := LoadProfile(path, name)
+		if err != nil {
+			return nil, err
+		}
+		envOpts = append(envOpts, WithProfile(profile))
+	}
+
+	if timeout := os.Getenv(EnvTimeout); timeout != "" {
+		d, err := parseTimeout(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("openai: invalid %s %q: %w", EnvTimeout, timeout, err)
+		}
+		envOpts = append(envOpts, WithTimeout(d))
+	}
+	envOpts = append(envOpts, WithProfile(Profile{
+		APIKey:       os.Getenv(EnvAPIKey),
+		BaseURL:      os.Getenv(EnvBaseURL),
+		Organization: os.Getenv(EnvOrgID),
+		Project:      os.Getenv(EnvProjectID),
+	}))
+	return NewClient(append(envOpts, opts...)...)
+}
+
+// parseTimeout accepts Go durations ("1m30s") and plain numbers of seconds ("90")
+func parseTimeout(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
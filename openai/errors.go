@@ -0,0 +1,44 @@
+package openai
+
+import (
+	"errors"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"net/http"
+)
+
+// ErrMissingAPIKey is returned by NewClient and NewFromEnv when no API key or credential provider is configured
+var ErrMissingAPIKey = errors.New("openai: api key cannot be empty")
+
+// ErrIdempotencyKeyReused is returned when the context carrying a RequestIdempotencyKey is used
+// for a second, different create operation
+var ErrIdempotencyKeyReused = transport.ErrIdempotencyKeyReused
+
+// APIError is returned by every subdomain client when OpenAI API responds with an error.
+// Use errors.As to access the status code, error type/code and the `x-request-id` of the failed call.
+type APIError = transport.APIError
+
+// IsRateLimited reports whether err is an APIError with 429 Too Many Requests status
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsNotFound reports whether err is an APIError with 404 Not Found status
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsAuth reports whether err is an APIError caused by an invalid API key or missing permissions
+func IsAuth(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized) || hasStatus(err, http.StatusForbidden)
+}
+
+// IsContextLengthExceeded reports whether the request messages didn't fit into the model context window
+func IsContextLengthExceeded(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == "context_length_exceeded"
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
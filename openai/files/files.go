@@ -2,23 +2,26 @@ package files
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
 )
 
-
 type FileClient interface {
 	UploadFile(filename string, fileData []byte) (string, error)
+	UploadFileContext(ctx context.Context, filename string, fileData []byte) (string, error)
 	DeleteFile(fileID string) error
+	DeleteFileContext(ctx context.Context, fileID string) error
 }
 
-// Files represents OpenAI API files domain
+// Files represents OpenAI API files domain.
+// Create, Get, List and Delete are the resource-style methods of the domain.
 type Files struct {
-	APIKey string
+	APIKey    string
+	Transport *transport.Client
 }
 
 // UploadFileResponse is used to unmarshal OpenAI API response in the UploadFile function
@@ -26,49 +29,54 @@ type UploadFileResponse struct {
 	ID string `json:"id"`
 }
 
+// File is used to unmarshal OpenAI API response in the Get and List functions
+type File struct {
+	ID        string `json:"id"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+}
+
+// ListFilesResponse is used to unmarshal OpenAI API response in the List function
+type ListFilesResponse struct {
+	Files []File `json:"data"`
+}
+
 // UploadFile uploads file with `filename` and binary data `fileData` into OpenAI portal
 // where can be later used by an assistant
 func (f Files) UploadFile(filename string, fileData []byte) (string, error) {
-	const URL = "https://api.openai.com/v1/files"
+	return f.UploadFileContext(context.Background(), filename, fileData)
+}
 
+// UploadFileContext is like UploadFile but uses `ctx` to cancel the outgoing request.
+func (f Files) UploadFileContext(ctx context.Context, filename string, fileData []byte) (string, error) {
 	var requestBody bytes.Buffer
 	writer := multipart.NewWriter(&requestBody)
-	_ = writer.WriteField("purpose", "assistants")
-
-	part, err := writer.CreateFormFile("file", filepath.Base(filename))
-	if err != nil {
-		return "", err
-	}
-	_, err = io.Copy(part, bytes.NewReader(fileData))
-	err = writer.Close()
-	if err != nil {
+	if err := writer.WriteField("purpose", "assistants"); err != nil {
 		return "", err
 	}
-	request, err := http.NewRequest("POST", URL, &requestBody)
-	request.Header.Set("Authorization", "Bearer "+f.APIKey)
-	request.Header.Set("Content-Type", writer.FormDataContentType())
-
-	client := &http.Client{}
-	resp, err := client.Do(request)
+	part, err := writer.CreateFormFile("file", filepath.Base(filename))
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("error uplaoding file: %d %s", resp.StatusCode, string(bodyBytes))
+	if _, err = part.Write(fileData); err != nil {
+		return "", fmt.Errorf("failed to write file data: %w", err)
 	}
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
+	if err = writer.Close(); err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("error deleting file: %d %s ", resp.StatusCode, string(responseBody))
-	}
+
 	var response UploadFileResponse
-	if err = json.Unmarshal(responseBody, &response); err != nil {
+	err = f.api().Call(ctx, transport.Request{
+		Endpoint:    "files.create",
+		Method:      http.MethodPost,
+		Path:        "/files",
+		RawBody:     requestBody.Bytes(),
+		ContentType: writer.FormDataContentType(),
+		Idempotent:  true,
+	}, &response)
+	if err != nil {
 		return "", err
 	}
 	return response.ID, nil
@@ -76,28 +84,79 @@ func (f Files) UploadFile(filename string, fileData []byte) (string, error) {
 
 // DeleteFile deletes file object specified by `fileID` from OpenAI portal
 func (f Files) DeleteFile(fileID string) error {
-	URL := "https://api.openai.com/v1/files/" + fileID
-	req, err := http.NewRequest(http.MethodDelete, URL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
-	}
-	req.Header.Set("Authorization", "Bearer "+f.APIKey)
+	return f.DeleteFileContext(context.Background(), fileID)
+}
+
+// DeleteFileContext is like DeleteFile but uses `ctx` to cancel the outgoing request.
+func (f Files) DeleteFileContext(ctx context.Context, fileID string) error {
+	return f.api().Call(ctx, transport.Request{
+		Endpoint: "files.delete",
+		Method:   http.MethodDelete,
+		Path:     "/files/" + fileID,
+	}, nil)
+}
 
-	// Create a new HTTP client and send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// Create is the resource-style name of UploadFile
+func (f Files) Create(filename string, fileData []byte) (string, error) {
+	return f.UploadFileContext(context.Background(), filename, fileData)
+}
+
+// CreateContext is like Create but uses `ctx` to cancel the outgoing request.
+func (f Files) CreateContext(ctx context.Context, filename string, fileData []byte) (string, error) {
+	return f.UploadFileContext(ctx, filename, fileData)
+}
+
+// Get returns the metadata of the file specified by `fileID`
+func (f Files) Get(fileID string) (File, error) {
+	return f.GetContext(context.Background(), fileID)
+}
+
+// GetContext is like Get but uses `ctx` to cancel the outgoing request.
+func (f Files) GetContext(ctx context.Context, fileID string) (File, error) {
+	var file File
+	err := f.api().Call(ctx, transport.Request{
+		Endpoint: "files.retrieve",
+		Method:   http.MethodGet,
+		Path:     "/files/" + fileID,
+	}, &file)
 	if err != nil {
-		return fmt.Errorf("request failed: %v", err)
+		return File{}, err
 	}
-	defer resp.Body.Close()
+	return file, nil
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
+// List returns the files uploaded to OpenAI portal
+func (f Files) List() (ListFilesResponse, error) {
+	return f.ListContext(context.Background())
+}
+
+// ListContext is like List but uses `ctx` to cancel the outgoing request.
+func (f Files) ListContext(ctx context.Context) (ListFilesResponse, error) {
+	var response ListFilesResponse
+	err := f.api().Call(ctx, transport.Request{
+		Endpoint: "files.list",
+		Method:   http.MethodGet,
+		Path:     "/files",
+	}, &response)
 	if err != nil {
-		return err
+		return ListFilesResponse{}, err
 	}
+	return response, nil
+}
+
+// Delete is the resource-style name of DeleteFile
+func (f Files) Delete(fileID string) error {
+	return f.DeleteFileContext(context.Background(), fileID)
+}
+
+// DeleteContext is like Delete but uses `ctx` to cancel the outgoing request.
+func (f Files) DeleteContext(ctx context.Context, fileID string) error {
+	return f.DeleteFileContext(ctx, fileID)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete file: %d %s ", resp.StatusCode, string(responseBody))
+func (f Files) api() *transport.Client {
+	if f.Transport != nil {
+		return f.Transport
 	}
-	return nil
+	return &transport.Client{APIKey: f.APIKey}
 }
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Azure switches Client to Azure OpenAI conventions: `api-key` authentication, the `api-version`
+// query parameter and deployment-scoped URLs for model endpoints. BaseURL must be the resource endpoint
+// (f.e. "https://my-resource.openai.azure.com").
+type Azure struct {
+	APIVersion string
+	// Deployments maps model names to Azure deployment names. Unmapped models are used as deployment names.
+	Deployments map[string]string
+}
+
+// deployment returns the deployment serving `model`
+func (a *Azure) deployment(model string) string {
+	if deployment, ok := a.Deployments[model]; ok {
+		return deployment
+	}
+	return model
+}
+
+// URL builds the Azure OpenAI URL of the endpoint `path` called for `model`
+func (a *Azure) URL(baseURL, path, model string) string {
+	baseURL = strings.TrimRight(baseURL, "/") + "/openai"
+	if model != "" {
+		path = "/deployments/" + url.PathEscape(a.deployment(model)) + path
+	}
+	return baseURL + path + "?api-version=" + url.QueryEscape(a.APIVersion)
+}
@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Request describes a single call of an OpenAI API endpoint
+type Request struct {
+	// Endpoint is a stable name of the operation reported to middlewares, f.e. "threads.runs.create"
+	Endpoint string
+	Method   string
+	// Path is the endpoint path relative to the base URL, f.e. "/threads/thread_abc/runs"
+	Path string
+	// Model is set for model-scoped endpoints (f.e. chat completions), which are deployment-scoped on Azure
+	Model string
+	// Body is marshalled to JSON when it is not nil
+	Body any
+	// RawBody is sent as is with ContentType instead of Body (f.e. multipart forms)
+	RawBody     []byte
+	ContentType string
+	// Assistants adds the `OpenAI-Beta: assistants=v2` header required by the assistants API
+	Assistants bool
+	// Retryable marks a non-idempotent request as safe to repeat. See RetryPolicy.
+	Retryable bool
+	// Idempotent sends the `Idempotency-Key` header, generated once per call unless supplied
+	// by the caller, so that retries of a create operation don't produce duplicates
+	Idempotent bool
+}
+
+// Call sends the request and decodes a successful JSON response into `out` (which may be nil).
+// Non-2xx responses are returned as *APIError.
+func (c *Client) Call(ctx context.Context, r Request, out any) error {
+	request, err := c.newRequest(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(r.Endpoint, request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if meta := callOptions(ctx).Meta; meta != nil {
+		*meta = NewResponseMeta(resp)
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewAPIError(resp, responseBody)
+	}
+	if out == nil {
+		return nil
+	}
+	if err = json.Unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", r.Method, r.Path, err)
+	}
+	return nil
+}
+
+// Stream sends the request and returns the successful response with the unread body,
+// f.e. a server-sent event stream. The caller must close the body.
+// Non-2xx responses are returned as *APIError.
+func (c *Client) Stream(ctx context.Context, r Request) (*http.Response, error) {
+	request, err := c.newRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.Do(r.Endpoint, request)
+	if err != nil {
+		return nil, err
+	}
+	if meta := callOptions(ctx).Meta; meta != nil {
+		*meta = NewResponseMeta(resp)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return nil, NewAPIError(resp, responseBody)
+	}
+	return resp, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, r Request) (*http.Request, error) {
+	if r.Retryable {
+		ctx = WithRetryable(ctx)
+	}
+
+	var body io.Reader
+	var payload []byte
+	contentType := r.ContentType
+	switch {
+	case r.RawBody != nil:
+		payload = r.RawBody
+		body = bytes.NewReader(payload)
+	case r.Body != nil:
+		var err error
+		payload, err = json.Marshal(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+
+	request, err := http.NewRequestWithContext(ctx, r.Method, c.URL(r.Path, r.Model), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if r.Assistants {
+		request.Header.Set("OpenAI-Beta", "assistants=v2")
+	}
+
+	callOpts := callOptions(ctx)
+	organization, project := callOpts.Organization, callOpts.Project
+	if c != nil {
+		organization = cmp.Or(organization, c.Organization)
+		project = cmp.Or(project, c.Project)
+	}
+	if organization != "" {
+		request.Header.Set("OpenAI-Organization", organization)
+	}
+	if project != "" {
+		request.Header.Set("OpenAI-Project", project)
+	}
+	if r.Idempotent {
+		key := newIdempotencyKey()
+		if callOpts.IdempotencyKey != nil {
+			if key, err = callOpts.IdempotencyKey.bind(operation(r.Method, r.Path, contentType, payload)); err != nil {
+				return nil, err
+			}
+		}
+		request.Header.Set(IdempotencyHeader, key)
+	}
+	for key, values := range callOpts.Headers {
+		request.Header[key] = values
+	}
+	return request, nil
+}
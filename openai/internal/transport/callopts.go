@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+// CallOptions override the client settings for the calls made with a context
+type CallOptions struct {
+	Organization string
+	Project      string
+	Headers      http.Header
+	// IdempotencyKey replaces the key generated for the first create operation made with the context
+	IdempotencyKey *IdempotencyKey
+	// Meta, when set, is filled with the metadata of the final response
+	Meta *ResponseMeta
+}
+
+// CallOption modifies CallOptions
+type CallOption func(*CallOptions)
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx carrying `opts` on top of the call options already stored in ctx
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	callOpts := callOptions(ctx)
+	callOpts.Headers = callOpts.Headers.Clone()
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, callOpts)
+}
+
+func callOptions(ctx context.Context) CallOptions {
+	callOpts, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+	return callOpts
+}
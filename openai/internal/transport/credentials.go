@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Credentials provides the API key for every attempt to send a request,
+// so that keys may be rotated without rebuilding the client
+type Credentials interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// Failover is implemented by Credentials holding several keys.
+// Failed is called when `apiKey` was rejected with 401 or 429 status code and reports
+// whether another key is available, in which case the request is sent again right away.
+type Failover interface {
+	Failed(apiKey string, statusCode int) bool
+}
+
+// authorize sets the authentication header of the request and returns the used key
+func (c *Client) authorize(request *http.Request) (string, error) {
+	apiKey := c.APIKey
+	if c.Credentials != nil {
+		var err error
+		if apiKey, err = c.Credentials.APIKey(request.Context()); err != nil {
+			return "", fmt.Errorf("failed to get api key: %w", err)
+		}
+	}
+	switch {
+	case apiKey == "":
+	case c.Azure != nil:
+		request.Header.Set("api-key", apiKey)
+	default:
+		request.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return apiKey, nil
+}
+
+// failover reports whether the request rejected for `apiKey` may be sent again with another key
+func (c *Client) failover(request *http.Request, apiKey string, resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	pool, ok := c.Credentials.(Failover)
+	if !ok || request.Body != nil && request.GetBody == nil {
+		return false
+	}
+	return pool.Failed(apiKey, resp.StatusCode)
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when OpenAI API responds with a non-successful status code.
+// The fields are parsed from the `{"error": {...}}` response body when it is present.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Param      string
+	Message    string
+	RequestID  string
+	Body       []byte
+}
+
+// NewAPIError builds an APIError from a failed response and its already read body
+func NewAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("x-request-id"),
+		Body:       body,
+	}
+
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Param   any    `json:"param"`
+			Code    any    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+	apiErr.Message = payload.Error.Message
+	apiErr.Type = payload.Error.Type
+	if payload.Error.Param != nil {
+		apiErr.Param = fmt.Sprint(payload.Error.Param)
+	}
+	if payload.Error.Code != nil {
+		apiErr.Code = fmt.Sprint(payload.Error.Code)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "openai: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	var details []string
+	if e.Type != "" {
+		details = append(details, "type: "+e.Type)
+	}
+	if e.Code != "" {
+		details = append(details, "code: "+e.Code)
+	}
+	if e.Param != "" {
+		details = append(details, "param: "+e.Param)
+	}
+	if e.RequestID != "" {
+		details = append(details, "request id: "+e.RequestID)
+	}
+	if len(details) > 0 {
+		b.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"mime"
+	"sync"
+)
+
+// IdempotencyHeader lets OpenAI API recognize a repeated request and return the result of the first one
+const IdempotencyHeader = "Idempotency-Key"
+
+// newIdempotencyKey returns a random UUID v4 prefixed with the library name
+func newIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("openai-go-%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// ErrIdempotencyKeyReused is returned when a caller-supplied idempotency key is sent with a second operation
+var ErrIdempotencyKeyReused = errors.New("openai: idempotency key is already used by another operation")
+
+// IdempotencyKey is a caller-supplied `Idempotency-Key`. It belongs to the first operation it is sent with
+// (the method, path and body of the request), so repeating that operation reuses the key
+// while a different operation fails with ErrIdempotencyKeyReused.
+type IdempotencyKey struct {
+	Key string
+
+	mu        sync.Mutex
+	operation string
+}
+
+// bind returns the key if `operation` (see the operation function) is the operation the key belongs to
+func (k *IdempotencyKey) bind(operation string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.operation == "" {
+		k.operation = operation
+	}
+	if k.operation != operation {
+		return "", fmt.Errorf("%w: %q is bound to %s, not %s", ErrIdempotencyKeyReused, k.Key, k.operation, operation)
+	}
+	return k.Key, nil
+}
+
+// operation identifies a request by its method, path and the hash of its body.
+// Multipart boundaries are random for every call, so they are left out of the hash.
+func operation(method, path, contentType string, body []byte) string {
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("%s %s (body %x)", method, path, sum[:8])
+}
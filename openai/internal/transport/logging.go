@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Logging configures structured logs of the requests sent by Client.
+// Request headers are never logged, and the API key is removed from logged bodies.
+type Logging struct {
+	Logger *slog.Logger
+	// Level is used for request start and successful finish records. Failures are logged as warnings.
+	Level slog.Level
+	// MaxBodyBytes enables logging of request and response bodies truncated to this size
+	MaxBodyBytes int
+}
+
+// middleware logs every attempt passing through it
+func (l *Logging) middleware(next Handler) Handler {
+	return func(call *Call) (*http.Response, error) {
+		ctx := call.Request.Context()
+		attrs := []slog.Attr{
+			slog.String("endpoint", call.Endpoint),
+			slog.String("method", call.Request.Method),
+			slog.String("path", call.Request.URL.Path),
+			slog.Int("attempt", call.Attempt),
+		}
+		secrets := credentials(call.Request.Header)
+
+		startAttrs := attrs
+		if l.MaxBodyBytes > 0 && call.Request.GetBody != nil {
+			if body, err := call.Request.GetBody(); err == nil {
+				startAttrs = append(startAttrs, slog.String("request_body", l.body(body, secrets)))
+			}
+		}
+		l.Logger.LogAttrs(ctx, l.Level, "openai request started", startAttrs...)
+
+		start := time.Now()
+		resp, err := next(call)
+		attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+		if err != nil {
+			attrs = append(attrs, slog.String("error", redact(err.Error(), secrets)))
+			l.Logger.LogAttrs(ctx, slog.LevelWarn, "openai request failed", attrs...)
+			return resp, err
+		}
+
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("request_id", resp.Header.Get("x-request-id")),
+		)
+		if l.MaxBodyBytes > 0 && !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+			body, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr == nil {
+				attrs = append(attrs, slog.String("response_body", l.body(bytes.NewReader(body), secrets)))
+			}
+		}
+		level := l.Level
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			level = slog.LevelWarn
+		}
+		l.Logger.LogAttrs(ctx, level, "openai request finished", attrs...)
+		return resp, err
+	}
+}
+
+func (l *Logging) retry(ctx context.Context, endpoint string, event RetryEvent) {
+	attrs := []slog.Attr{
+		slog.String("endpoint", endpoint),
+		slog.String("method", event.Method),
+		slog.Int("attempt", event.Attempt),
+		slog.Duration("delay", event.Delay),
+	}
+	if event.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", event.StatusCode))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	l.Logger.LogAttrs(ctx, slog.LevelInfo, "openai request retrying", attrs...)
+}
+
+// body reads at most MaxBodyBytes of the body and removes the secrets from it.
+// Secrets are removed before truncation, so that a key cut in half never leaks.
+func (l *Logging) body(body io.Reader, secrets []string) string {
+	limit := l.MaxBodyBytes
+	for _, secret := range secrets {
+		limit += len(secret)
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	text := redact(string(data), secrets)
+	if len(text) > l.MaxBodyBytes {
+		return text[:l.MaxBodyBytes] + "...(truncated)"
+	}
+	return text
+}
+
+// credentials returns the API keys sent with the request
+func credentials(header http.Header) []string {
+	var secrets []string
+	if token := strings.TrimSpace(strings.TrimPrefix(header.Get("Authorization"), "Bearer ")); token != "" {
+		secrets = append(secrets, token)
+	}
+	if key := header.Get("api-key"); key != "" {
+		secrets = append(secrets, key)
+	}
+	return secrets
+}
+
+func redact(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	return text
+}
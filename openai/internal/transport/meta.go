@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response of a call: useful for support tickets and throughput tuning
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the `x-request-id` header, which OpenAI support asks for
+	RequestID string
+	// ProcessingTime is the `openai-processing-ms` header
+	ProcessingTime time.Duration
+	// Model is the `openai-model` header
+	Model string
+	// Organization is the `openai-organization` header
+	Organization string
+	RateLimit    RateLimitInfo
+}
+
+// RateLimitInfo is parsed from the `x-ratelimit-*` headers. Zero values mean the header was absent.
+type RateLimitInfo struct {
+	LimitRequests     int64
+	LimitTokens       int64
+	RemainingRequests int64
+	RemainingTokens   int64
+	ResetRequests     time.Duration
+	ResetTokens       time.Duration
+}
+
+// NewResponseMeta parses the metadata of `resp`
+func NewResponseMeta(resp *http.Response) ResponseMeta {
+	header := resp.Header
+	meta := ResponseMeta{
+		StatusCode:   resp.StatusCode,
+		Header:       header.Clone(),
+		RequestID:    header.Get("x-request-id"),
+		Model:        header.Get("openai-model"),
+		Organization: header.Get("openai-organization"),
+	}
+	if ms, err := strconv.ParseFloat(header.Get("openai-processing-ms"), 64); err == nil {
+		meta.ProcessingTime = time.Duration(ms * float64(time.Millisecond))
+	}
+	meta.RateLimit.LimitRequests = parseInt(header.Get("x-ratelimit-limit-requests"))
+	meta.RateLimit.LimitTokens = parseInt(header.Get("x-ratelimit-limit-tokens"))
+	meta.RateLimit.RemainingRequests = parseInt(header.Get("x-ratelimit-remaining-requests"))
+	meta.RateLimit.RemainingTokens = parseInt(header.Get("x-ratelimit-remaining-tokens"))
+	meta.RateLimit.ResetRequests, _ = parseReset(header.Get("x-ratelimit-reset-requests"))
+	meta.RateLimit.ResetTokens, _ = parseReset(header.Get("x-ratelimit-reset-tokens"))
+	return meta
+}
+
+func parseInt(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"net/http"
+)
+
+// Call is a single attempt to send a request to an OpenAI API endpoint
+type Call struct {
+	// Endpoint is a stable name of the called operation, f.e. "chat.completions.create"
+	Endpoint string
+	// Attempt is 1 for the first try and grows with every retry
+	Attempt int
+	// Request is the outgoing request. Middlewares may modify its headers.
+	Request *http.Request
+}
+
+// Handler sends the call and returns OpenAI API response
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler to observe or modify the traffic of every subdomain client.
+// A middleware reading the response body must replace it with an unread copy.
+type Middleware func(next Handler) Handler
+
+// chain builds a handler where the first middleware is the outermost one
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter blocks outgoing requests which would certainly be rejected by OpenAI API
+// because the `x-ratelimit-remaining-*` values reported by previous responses are exhausted.
+// Limits are tracked separately for every model, since OpenAI API applies them per model.
+// The zero value is ready to use and may be shared between several clients.
+type RateLimiter struct {
+	mu     sync.Mutex
+	models map[string]*modelLimits
+}
+
+type modelLimits struct {
+	requests limitState
+	tokens   limitState
+}
+
+// limitState is the last known remaining quota and the moment it is restored
+type limitState struct {
+	known     bool
+	remaining int64
+	resetAt   time.Time
+}
+
+// Wait blocks until a request to `model` estimated to cost `tokens` fits into the known limits,
+// then reserves the request and its tokens. It returns early with an error when ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, model string, tokens int64) error {
+	for {
+		delay := l.reserve(model, tokens)
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *RateLimiter) reserve(model string, tokens int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	limits := l.limits(model)
+	limits.requests.expire(now)
+	limits.tokens.expire(now)
+
+	var delay time.Duration
+	if limits.requests.known && limits.requests.remaining < 1 {
+		delay = max(delay, limits.requests.resetAt.Sub(now))
+	}
+	if limits.tokens.known && limits.tokens.remaining < tokens {
+		delay = max(delay, limits.tokens.resetAt.Sub(now))
+	}
+	if delay > 0 {
+		return delay
+	}
+	if limits.requests.known {
+		limits.requests.remaining--
+	}
+	if limits.tokens.known {
+		limits.tokens.remaining -= tokens
+	}
+	return 0
+}
+
+// Update records the rate limit state reported in the response headers
+func (l *RateLimiter) Update(model string, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	limits := l.limits(model)
+	limits.requests.update(now, header, "requests")
+	limits.tokens.update(now, header, "tokens")
+}
+
+func (l *RateLimiter) limits(model string) *modelLimits {
+	if l.models == nil {
+		l.models = make(map[string]*modelLimits)
+	}
+	limits, ok := l.models[model]
+	if !ok {
+		limits = &modelLimits{}
+		l.models[model] = limits
+	}
+	return limits
+}
+
+func (s *limitState) update(now time.Time, header http.Header, limit string) {
+	remaining, err := strconv.ParseInt(header.Get("x-ratelimit-remaining-"+limit), 10, 64)
+	if err != nil {
+		return
+	}
+	reset, ok := parseReset(header.Get("x-ratelimit-reset-" + limit))
+	if !ok {
+		return
+	}
+	*s = limitState{
+		known:     true,
+		remaining: remaining,
+		resetAt:   now.Add(reset),
+	}
+}
+
+// expire forgets the state once the limit has been restored
+func (s *limitState) expire(now time.Time) {
+	if s.known && !now.Before(s.resetAt) {
+		*s = limitState{}
+	}
+}
+
+// estimateCost returns the model of a JSON request and a rough estimation of tokens it will consume:
+// ~4 bytes of payload per token plus the requested completion limit.
+func estimateCost(request *http.Request) (string, int64) {
+	if request.GetBody == nil || !strings.HasPrefix(request.Header.Get("Content-Type"), "application/json") {
+		return "", 0
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return "", 0
+	}
+	defer body.Close()
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return "", 0
+	}
+
+	var fields struct {
+		Model               string `json:"model"`
+		MaxTokens           int64  `json:"max_tokens"`
+		MaxCompletionTokens int64  `json:"max_completion_tokens"`
+	}
+	_ = json.Unmarshal(payload, &fields)
+	tokens := int64(len(payload))/4 + max(fields.MaxTokens, fields.MaxCompletionTokens)
+	return fields.Model, tokens
+}
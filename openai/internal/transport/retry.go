@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy describes when and how often failed requests are sent again.
+// Requests rejected with 429 Too Many Requests are always retried, because OpenAI API didn't process them.
+// Connection errors, 408, 409 and 5xx responses are retried only for idempotent methods,
+// requests carrying an `Idempotency-Key` header and requests explicitly marked as safe to repeat.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles with every next attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. Delays requested by the server are not capped.
+	MaxBackoff time.Duration
+	// OnRetry, when set, is called before waiting for the next attempt
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt which is about to be retried
+type RetryEvent struct {
+	Attempt    int
+	Method     string
+	URL        string
+	StatusCode int
+	Err        error
+	Delay      time.Duration
+}
+
+type retryableKey struct{}
+
+// WithRetryable marks requests made with the returned context as safe to repeat
+// even though their HTTP method is not idempotent (f.e. stateless POST /chat/completions).
+func WithRetryable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableKey{}, true)
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns a jittered exponential delay for the given (1-based) failed attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	maxDelay := p.MaxBackoff
+	if maxDelay <= 0 {
+		maxDelay = 8 * time.Second
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// shouldRetry decides whether the attempt which ended with `resp` or `err` may be repeated
+func shouldRetry(request *http.Request, resp *http.Response, err error) bool {
+	if request.Body != nil && request.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return request.Context().Err() == nil && isSafe(request)
+	}
+	switch resp.Header.Get("x-should-retry") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusConflict,
+		resp.StatusCode >= http.StatusInternalServerError:
+		return isSafe(request)
+	}
+	return false
+}
+
+func isSafe(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if request.Header.Get(IdempotencyHeader) != "" {
+		return true
+	}
+	retryable, _ := request.Context().Value(retryableKey{}).(bool)
+	return retryable
+}
+
+// serverDelay returns the delay requested by OpenAI API in `retry-after-ms`, `Retry-After`
+// or, for exhausted rate limits, `x-ratelimit-reset-*` headers.
+func serverDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if ms, err := strconv.ParseFloat(resp.Header.Get("retry-after-ms"), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return max(time.Until(date), 0), true
+		}
+	}
+	var delay time.Duration
+	found := false
+	for _, limit := range []string{"requests", "tokens"} {
+		if resp.Header.Get("x-ratelimit-remaining-"+limit) != "0" {
+			continue
+		}
+		if reset, ok := parseReset(resp.Header.Get("x-ratelimit-reset-" + limit)); ok {
+			delay = max(delay, reset)
+			found = true
+		}
+	}
+	return delay, found
+}
+
+// parseReset parses `x-ratelimit-reset-*` values such as "1s", "6m0s" or "20ms"
+func parseReset(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, true
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// rewind prepares the request body to be sent once again
+func rewind(request *http.Request) error {
+	if request.GetBody == nil {
+		return nil
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return err
+	}
+	request.Body = body
+	return nil
+}
+
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Event is a single server-sent event
+type Event struct {
+	// Name is the `event:` field, empty for the default "message" events
+	Name string
+	// Data is the `data:` field, lines of a multi-line field are joined with "\n"
+	Data []byte
+}
+
+// EventReader parses a text/event-stream body into events
+type EventReader struct {
+	r *bufio.Reader
+}
+
+// NewEventReader returns an EventReader reading from `r`
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next event of the stream or io.EOF when the stream ends.
+// Comments, `id:` and `retry:` fields are skipped.
+func (e *EventReader) Next() (Event, error) {
+	var event Event
+	var data [][]byte
+	for {
+		line, err := e.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			if err == io.EOF && (data != nil || event.Name != "") {
+				// the last event isn't followed by a blank line
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			return Event{}, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		if len(line) == 0 {
+			if data == nil && event.Name == "" {
+				continue
+			}
+			event.Data = bytes.Join(data, []byte("\n"))
+			return event, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event.Name = string(value)
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultBaseURL is the OpenAI REST API root used when no base URL is configured
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// Client holds connection settings shared by every OpenAI API subdomain client.
+// A nil *Client is valid and sends requests to DefaultBaseURL with http.DefaultClient.
+type Client struct {
+	// APIKey is used when Credentials is nil
+	APIKey       string
+	Credentials  Credentials
+	Organization string
+	Project      string
+	BaseURL      string
+	HTTPClient   *http.Client
+	Headers      http.Header
+	Retry        *RetryPolicy
+	Limiter      *RateLimiter
+	Middleware   []Middleware
+	Logging      *Logging
+	Azure        *Azure
+}
+
+// URL joins the endpoint `path` (f.e. "/chat/completions") onto the configured base URL.
+// `model` is used only by Azure OpenAI to pick the deployment of model-scoped endpoints.
+func (c *Client) URL(path, model string) string {
+	baseURL := DefaultBaseURL
+	if c != nil && c.BaseURL != "" {
+		baseURL = c.BaseURL
+	}
+	if c != nil && c.Azure != nil {
+		return c.Azure.URL(baseURL, path, model)
+	}
+	return strings.TrimRight(baseURL, "/") + path
+}
+
+// ModelName returns the value of the `model` field expected by the API in request bodies:
+// the deployment name on Azure OpenAI and `model` itself otherwise.
+func (c *Client) ModelName(model string) string {
+	if c != nil && c.Azure != nil && model != "" {
+		return c.Azure.deployment(model)
+	}
+	return model
+}
+
+// Do applies the default headers to the request and sends it through the middleware chain
+// with the shared http.Client, waiting for the rate limiter and retrying failed attempts
+// according to the retry policy. Headers already set on the request take precedence over the defaults.
+func (c *Client) Do(endpoint string, request *http.Request) (*http.Response, error) {
+	if c == nil {
+		return http.DefaultClient.Do(request)
+	}
+	for key, values := range c.Headers {
+		if request.Header.Get(key) != "" {
+			continue
+		}
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	middleware := c.Middleware
+	if c.Logging != nil && c.Logging.Logger != nil {
+		// logging is the innermost middleware, so it records the request exactly as it is sent
+		middleware = append(middleware[:len(middleware):len(middleware)], c.Logging.middleware)
+	}
+	send := chain(func(call *Call) (*http.Response, error) {
+		return httpClient.Do(call.Request)
+	}, middleware)
+
+	var model string
+	var tokens int64
+	if c.Limiter != nil {
+		model, tokens = estimateCost(request)
+	}
+
+	attempts := c.Retry.attempts()
+	for attempt, tries := 1, 1; ; tries++ {
+		apiKey, err := c.authorize(request)
+		if err != nil {
+			return nil, err
+		}
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(request.Context(), model, tokens); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := send(&Call{
+			Endpoint: endpoint,
+			Attempt:  tries,
+			Request:  request,
+		})
+		if c.Limiter != nil && resp != nil {
+			c.Limiter.Update(model, resp.Header)
+		}
+		if c.failover(request, apiKey, resp) {
+			// another key is available, so the request is sent again without waiting
+			discard(resp)
+			if err := rewind(request); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if attempt >= attempts || !shouldRetry(request, resp, err) {
+			return resp, err
+		}
+
+		delay, ok := serverDelay(resp)
+		if !ok {
+			delay = c.Retry.backoff(attempt)
+		}
+		event := RetryEvent{
+			Attempt: attempt,
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Err:     err,
+			Delay:   delay,
+		}
+		if resp != nil {
+			event.StatusCode = resp.StatusCode
+		}
+		discard(resp)
+		if c.Logging != nil && c.Logging.Logger != nil {
+			c.Logging.retry(request.Context(), endpoint, event)
+		}
+		if c.Retry.OnRetry != nil {
+			c.Retry.OnRetry(event)
+		}
+
+		if err := sleep(request.Context(), delay); err != nil {
+			return nil, err
+		}
+		if err := rewind(request); err != nil {
+			return nil, err
+		}
+		attempt++
+	}
+}
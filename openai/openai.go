@@ -8,8 +8,11 @@ import (
 	vecstores "github.com/ilborsch/openai-go/openai/assistants/vector-stores"
 	"github.com/ilborsch/openai-go/openai/chatgpt"
 	"github.com/ilborsch/openai-go/openai/files"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"log/slog"
 )
 
+// OpenAIClient is the flat interface of all OpenAI API methods, implemented by *OpenAI
 type OpenAIClient interface {
 	chatgpt.ChatGPTClient
 	files.FileClient
@@ -21,41 +24,112 @@ type OpenAIClient interface {
 // ( f.e. `OpenAI.Assistants.VectorStores.Create(...)` ),
 // or alternatively by shortened syntax ( f.e. `OpenAI.VectorStores.Create(...)` ).
 // Both examples do absolutely the same, it's just a syntax sugar from Go language.
+// The flat methods of OpenAIClient (f.e. `OpenAI.CreateVectorStore(...)`) are available as well.
 type OpenAI struct {
-	apiKey string
-	chatgpt.ChatGPTClient
-	files.FileClient
-	assistants.AssistantClient
+	*chatgpt.ChatGPT
+	*files.Files
+	*assistants.Assistants
+
+	// Chat is the same service as ChatGPT
+	Chat         *chatgpt.ChatGPT
+	Threads      *threads.Threads
+	Messages     *messages.Messages
+	Runs         *runs.Runs
+	VectorStores *vecstores.VectorStores
 }
 
-// New initializes a new OpenAI instance and returns it
-func New(apiKey string) OpenAIClient {
-	if apiKey == "" {
-		panic("api key cannot be empty")
+var _ OpenAIClient = (*OpenAI)(nil)
+
+// New initializes a new OpenAI instance and returns it.
+// Options (f.e. WithBaseURL or WithHTTPClient) are shared by all subdomain clients.
+// `apiKey` may be empty only when WithCredentials is passed.
+// New panics on invalid configuration; use NewClient or NewFromEnv to get an error instead.
+func New(apiKey string, opts ...Option) *OpenAI {
+	client, err := newClient(nil, append([]Option{WithAPIKey(apiKey)}, opts...))
+	if err != nil {
+		panic(err)
 	}
-	return OpenAI{
-		apiKey: apiKey,
-		ChatGPTClient: chatgpt.ChatGPT{
-			APIKey: apiKey,
-			Model:  chatgpt.DefaultModel,
-		},
-		FileClient: files.Files{
-			APIKey: apiKey,
-		},
-		AssistantClient: assistants.Assistants{
-			APIKey: apiKey,
-			VectorStoreClient: vecstores.VectorStores{
-				APIKey: apiKey,
-			},
-			MessageClient: messages.Messages{
-				APIKey: apiKey,
-			},
-			RunClient: runs.Runs{
-				APIKey: apiKey,
-			},
-			ThreadClient: threads.Threads{
-				APIKey: apiKey,
-			},
-		},
+	return client
+}
+
+// NewClient initializes a new OpenAI instance configured only by `opts`.
+// Unlike New it returns an error when the configuration is invalid, f.e. ErrMissingAPIKey
+// when neither WithAPIKey nor WithCredentials is passed.
+func NewClient(opts ...Option) (*OpenAI, error) {
+	return newClient(nil, opts)
+}
+
+// NewAzure initializes a new OpenAI instance working with Azure OpenAI resource at `endpoint`
+// (f.e. "https://my-resource.openai.azure.com") through the same API as New.
+// `deployments` maps model names (f.e. chatgpt.DefaultModel) to the names of Azure deployments;
+// models missing in the map are used as deployment names.
+func NewAzure(endpoint, apiKey, apiVersion string, deployments map[string]string, opts ...Option) *OpenAI {
+	if endpoint == "" {
+		panic("azure endpoint cannot be empty")
+	}
+	if apiVersion == "" {
+		panic("azure api version cannot be empty")
+	}
+	opts = append([]Option{WithAPIKey(apiKey)}, append(opts, WithBaseURL(endpoint))...)
+	client, err := newClient(&transport.Azure{
+		APIVersion:  apiVersion,
+		Deployments: deployments,
+	}, opts)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func newClient(azure *transport.Azure, opts []Option) (*OpenAI, error) {
+	o := options{
+		logging: transport.Logging{Level: slog.LevelDebug},
 	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	apiKey := o.apiKey
+	t := &transport.Client{
+		APIKey:       apiKey,
+		Credentials:  o.credentials,
+		Organization: o.organization,
+		Project:      o.project,
+		BaseURL:      o.baseURL,
+		HTTPClient:   o.httpClientOrDefault(),
+		Headers:      o.headers,
+		Retry:        o.retry,
+		Limiter:      o.limiter,
+		Middleware:   o.middleware,
+		Logging:      &o.logging,
+		Azure:        azure,
+	}
+	chat := &chatgpt.ChatGPT{
+		APIKey:    apiKey,
+		Model:     chatgpt.DefaultModel,
+		Transport: t,
+	}
+	vectorStores := &vecstores.VectorStores{APIKey: apiKey, Transport: t}
+	messagesService := &messages.Messages{APIKey: apiKey, Transport: t}
+	runsService := &runs.Runs{APIKey: apiKey, Transport: t}
+	threadsService := &threads.Threads{APIKey: apiKey, Transport: t}
+	return &OpenAI{
+		ChatGPT: chat,
+		Files:   &files.Files{APIKey: apiKey, Transport: t},
+		Assistants: &assistants.Assistants{
+			APIKey:       apiKey,
+			Transport:    t,
+			VectorStores: vectorStores,
+			Messages:     messagesService,
+			Runs:         runsService,
+			Threads:      threadsService,
+		},
+		Chat:         chat,
+		Threads:      threadsService,
+		Messages:     messagesService,
+		Runs:         runsService,
+		VectorStores: vectorStores,
+	}, nil
 }
@@ -0,0 +1,350 @@
+package openaitest
+
+import (
+	"net/http"
+	"time"
+)
+
+type assistant struct {
+	ID            string           `json:"id"`
+	Object        string           `json:"object"`
+	CreatedAt     int64            `json:"created_at"`
+	Name          string           `json:"name"`
+	Model         string           `json:"model"`
+	Instructions  string           `json:"instructions"`
+	Tools         []map[string]any `json:"tools"`
+	ToolResources map[string]any   `json:"tool_resources"`
+	Temperature   float64          `json:"temperature"`
+}
+
+type thread struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	CreatedAt int64  `json:"created_at"`
+	messages  []*threadMessage
+	runs      map[string]*run
+}
+
+type threadMessage struct {
+	ID          string           `json:"id"`
+	Object      string           `json:"object"`
+	CreatedAt   int64            `json:"created_at"`
+	ThreadID    string           `json:"thread_id"`
+	Role        string           `json:"role"`
+	Content     []map[string]any `json:"content"`
+	AssistantID string           `json:"assistant_id,omitempty"`
+	RunID       string           `json:"run_id,omitempty"`
+}
+
+type run struct {
+	ID          string `json:"id"`
+	Object      string `json:"object"`
+	CreatedAt   int64  `json:"created_at"`
+	ThreadID    string `json:"thread_id"`
+	AssistantID string `json:"assistant_id"`
+	Status      string `json:"status"`
+	// next are the statuses the run goes through on the following retrievals
+	next []string
+}
+
+// SetRunStatuses scripts the statuses new runs go through: a run is created with the first status
+// and moves to the next one every time it is retrieved. When a run reaches "completed",
+// the assistant reply is added to its thread. By default runs are completed right away.
+func (s *Server) SetRunStatuses(statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runStatuses = statuses
+}
+
+// QueueRunReply makes the next completed runs add `replies` to their threads, one per run.
+// By default the assistant echoes the last user message of the thread.
+func (s *Server) QueueRunReply(replies ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runReplies = append(s.runReplies, replies...)
+}
+
+func (s *Server) createAssistant(w http.ResponseWriter, r *http.Request) {
+	var payload assistant
+	if !decode(w, r, &payload) {
+		return
+	}
+	if payload.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Missing required parameter: 'model'.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fileSearch, _ := payload.ToolResources["file_search"].(map[string]any)
+	if storeIDs, ok := fileSearch["vector_store_ids"].([]any); ok {
+		for _, storeID := range storeIDs {
+			id, _ := storeID.(string)
+			if _, ok := s.vectorStores[id]; !ok {
+				notFound(w, "vector store", id)
+				return
+			}
+		}
+	}
+	payload.ID = s.newID("asst")
+	payload.Object = "assistant"
+	payload.CreatedAt = time.Now().Unix()
+	payload.Temperature = 1
+	s.assistants[payload.ID] = &payload
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (s *Server) getAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := r.PathValue("assistant_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.assistants[assistantID]
+	if !ok {
+		notFound(w, "assistant", assistantID)
+		return
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (s *Server) listAssistants(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, list(newestFirst(s.assistants)))
+}
+
+func (s *Server) modifyAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := r.PathValue("assistant_id")
+	var payload struct {
+		Name         *string  `json:"name"`
+		Model        *string  `json:"model"`
+		Instructions *string  `json:"instructions"`
+		Temperature  *float64 `json:"temperature"`
+	}
+	if !decode(w, r, &payload) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.assistants[assistantID]
+	if !ok {
+		notFound(w, "assistant", assistantID)
+		return
+	}
+	if payload.Name != nil {
+		a.Name = *payload.Name
+	}
+	if payload.Model != nil {
+		a.Model = *payload.Model
+	}
+	if payload.Instructions != nil {
+		a.Instructions = *payload.Instructions
+	}
+	if payload.Temperature != nil {
+		a.Temperature = *payload.Temperature
+	}
+	writeJSON(w, http.StatusOK, a)
+}
+
+func (s *Server) deleteAssistant(w http.ResponseWriter, r *http.Request) {
+	assistantID := r.PathValue("assistant_id")
+	s.mu.Lock()
+	_, ok := s.assistants[assistantID]
+	delete(s.assistants, assistantID)
+	s.mu.Unlock()
+	if !ok {
+		notFound(w, "assistant", assistantID)
+		return
+	}
+	writeJSON(w, http.StatusOK, deleted(assistantID, "assistant"))
+}
+
+func (s *Server) createThread(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	t := &thread{
+		ID:        s.newID("thread"),
+		Object:    "thread",
+		CreatedAt: time.Now().Unix(),
+		runs:      make(map[string]*run),
+	}
+	s.threads[t.ID] = t
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) getThread(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) deleteThread(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	s.mu.Lock()
+	_, ok := s.threads[threadID]
+	delete(s.threads, threadID)
+	s.mu.Unlock()
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	writeJSON(w, http.StatusOK, deleted(threadID, "thread"))
+}
+
+func (s *Server) addMessage(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	var payload struct {
+		Role    string `json:"role"`
+		Content any    `json:"content"`
+	}
+	if !decode(w, r, &payload) {
+		return
+	}
+	if payload.Role != "user" && payload.Role != "assistant" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid 'role': expected 'user' or 'assistant'.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.appendMessage(t, payload.Role, textContent(payload.Content), ""))
+}
+
+func (s *Server) listMessages(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	// newest messages first, like the default `order=desc` of OpenAI API
+	data := make([]*threadMessage, 0, len(t.messages))
+	for i := len(t.messages) - 1; i >= 0; i-- {
+		data = append(data, t.messages[i])
+	}
+	writeJSON(w, http.StatusOK, list(data))
+}
+
+func (s *Server) createRun(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	var payload struct {
+		AssistantID string `json:"assistant_id"`
+	}
+	if !decode(w, r, &payload) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	if _, ok := s.assistants[payload.AssistantID]; !ok {
+		notFound(w, "assistant", payload.AssistantID)
+		return
+	}
+
+	statuses := s.runStatuses
+	if len(statuses) == 0 {
+		statuses = []string{"completed"}
+	}
+	ru := &run{
+		ID:          s.newID("run"),
+		Object:      "thread.run",
+		CreatedAt:   time.Now().Unix(),
+		ThreadID:    threadID,
+		AssistantID: payload.AssistantID,
+		next:        append([]string(nil), statuses...),
+	}
+	t.runs[ru.ID] = ru
+	s.advance(t, ru)
+	writeJSON(w, http.StatusOK, ru)
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	threadID := r.PathValue("thread_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	writeJSON(w, http.StatusOK, list(newestFirst(t.runs)))
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	threadID, runID := r.PathValue("thread_id"), r.PathValue("run_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.threads[threadID]
+	if !ok {
+		notFound(w, "thread", threadID)
+		return
+	}
+	ru, ok := t.runs[runID]
+	if !ok {
+		notFound(w, "run", runID)
+		return
+	}
+	s.advance(t, ru)
+	writeJSON(w, http.StatusOK, ru)
+}
+
+// advance moves the run to its next scripted status, adding the assistant reply on completion
+func (s *Server) advance(t *thread, ru *run) {
+	if len(ru.next) == 0 {
+		return
+	}
+	ru.Status, ru.next = ru.next[0], ru.next[1:]
+	if ru.Status != "completed" {
+		return
+	}
+
+	var reply string
+	if len(s.runReplies) > 0 {
+		reply, s.runReplies = s.runReplies[0], s.runReplies[1:]
+	} else {
+		var lastUser string
+		for _, m := range t.messages {
+			if m.Role == "user" {
+				lastUser = m.Content[0]["text"].(map[string]any)["value"].(string)
+			}
+		}
+		reply = "You said: " + lastUser
+	}
+	message := s.appendMessage(t, "assistant", reply, ru.ID)
+	message.AssistantID = ru.AssistantID
+}
+
+func (s *Server) appendMessage(t *thread, role, text, runID string) *threadMessage {
+	message := &threadMessage{
+		ID:        s.newID("msg"),
+		Object:    "thread.message",
+		CreatedAt: time.Now().Unix(),
+		ThreadID:  t.ID,
+		Role:      role,
+		Content: []map[string]any{{
+			"type": "text",
+			"text": map[string]any{"value": text, "annotations": []any{}},
+		}},
+		RunID: runID,
+	}
+	t.messages = append(t.messages, message)
+	return message
+}
@@ -0,0 +1,288 @@
+package openaitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder records real traffic or replays a cassette
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette without touching the network
+	ModeReplay Mode = iota
+	// ModeRecord sends requests through Recorder.Transport and appends them to the cassette
+	ModeRecord
+)
+
+// ErrNoCassette is returned by NewRecorder in ModeReplay when the cassette file doesn't exist
+var ErrNoCassette = errors.New("openaitest: cassette not found")
+
+// scrubbedHeaders are request headers never written to cassettes
+var scrubbedHeaders = []string{"Authorization", "Api-Key", "Openai-Organization", "Openai-Project"}
+
+// scrubbedResponseHeaders are response headers never written to cassettes: the account and session cookies
+var scrubbedResponseHeaders = []string{"Openai-Organization", "Openai-Project", "Set-Cookie"}
+
+// Interaction is a request/response pair stored in a cassette
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// BodyBase64 is the BodyEncoding of recorded bodies which aren't valid UTF-8, f.e. uploaded PDF or ZIP files
+const BodyBase64 = "base64"
+
+// RecordedRequest is a request stored in a cassette. Authentication headers are scrubbed.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// BodyEncoding is BodyBase64 when Body is base64-encoded and empty when it is stored as is
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is a response stored in a cassette. Account headers and cookies are scrubbed.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// BodyEncoding is BodyBase64 when Body is base64-encoded and empty when it is stored as is
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording OpenAI API traffic to a cassette file
+// or replaying it from one. Requests are matched by method, path and normalized body
+// (JSON is compared regardless of formatting and key order, multipart boundaries are ignored).
+// Recorded interactions are replayed once each in order; when all matching interactions were used,
+// the last one is repeated, so polling loops (f.e. GetRun) may run longer than when recorded.
+//
+//	recorder, err := openaitest.NewRecorder("testdata/chat.json", openaitest.ModeReplay)
+//	client := openai.New("sk-any", openai.WithHTTPClient(recorder.Client()))
+type Recorder struct {
+	// Path is the cassette file
+	Path string
+	Mode Mode
+	// Transport sends requests in ModeRecord. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder creates a Recorder for the cassette at `path`. In ModeReplay the cassette must exist.
+// In ModeRecord the recorded interactions replace the cassette on Save.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode}
+	if mode != ModeReplay {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoCassette, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("openaitest: read cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("openaitest: decode cassette %s: %w", path, err)
+	}
+	r.interactions = c.Interactions
+	r.used = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Client returns an http.Client sending requests through the Recorder, to be passed to openai.WithHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		_ = request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Mode == ModeReplay {
+		return r.replay(request, body)
+	}
+	return r.record(request, body)
+}
+
+func (r *Recorder) replay(request *http.Request, body []byte) (*http.Response, error) {
+	key := normalizeBody(request.Header.Get("Content-Type"), body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, interaction := range r.interactions {
+		recorded := interaction.Request
+		if recorded.Method != request.Method || requestPath(recorded.URL) != request.URL.Path ||
+			normalizeBody(recorded.Header.Get("Content-Type"), decodeBody(recorded.Body, recorded.BodyEncoding)) != key {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response.response(request), nil
+		}
+	}
+	if last == -1 {
+		return nil, fmt.Errorf("openaitest: no interaction for %s %s in cassette %s", request.Method, request.URL.Path, r.Path)
+	}
+	return r.interactions[last].Response.response(request), nil
+}
+
+func (r *Recorder) record(request *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	outgoing := request.Clone(request.Context())
+	outgoing.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := transport.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := request.Header.Clone()
+	for _, key := range scrubbedHeaders {
+		header.Del(key)
+	}
+	responseHeader := resp.Header.Clone()
+	for _, key := range scrubbedResponseHeaders {
+		responseHeader.Del(key)
+	}
+	recorded := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     responseHeader,
+	}
+	recorded.Body, recorded.BodyEncoding = encodeBody(responseBody)
+	recordedRequest := RecordedRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Header: header,
+	}
+	recordedRequest.Body, recordedRequest.BodyEncoding = encodeBody(body)
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request:  recordedRequest,
+		Response: recorded,
+	})
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+	return recorded.response(request), nil
+}
+
+// Save writes the interactions to the cassette file, creating its directory if needed.
+// It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.Mode == ModeReplay {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.Path, append(data, '\n'), 0o644)
+}
+
+func (rr RecordedResponse) response(request *http.Request) *http.Response {
+	body := decodeBody(rr.Body, rr.BodyEncoding)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rr.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
+
+// encodeBody returns `body` as a string with its BodyEncoding: base64 when it isn't valid UTF-8,
+// which encoding/json would replace with U+FFFD
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), BodyBase64
+}
+
+// decodeBody returns the bytes of a body stored by encodeBody
+func decodeBody(body, encoding string) []byte {
+	if encoding == BodyBase64 {
+		if decoded, err := base64.StdEncoding.DecodeString(body); err == nil {
+			return decoded
+		}
+	}
+	return []byte(body)
+}
+
+func requestPath(rawURL string) string {
+	request, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return rawURL
+	}
+	return request.URL.Path
+}
+
+// normalizeBody makes equal payloads compare equal: JSON is re-encoded with sorted keys
+// and multipart boundaries are replaced with a fixed one
+func normalizeBody(contentType string, body []byte) string {
+	var v any
+	if json.Unmarshal(body, &v) == nil {
+		normalized, err := json.Marshal(v)
+		if err == nil {
+			return string(normalized)
+		}
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		return strings.ReplaceAll(string(body), params["boundary"], "boundary")
+	}
+	return string(body)
+}
@@ -0,0 +1,339 @@
+package openaitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CompletionRequest is a chat completion request received by the Server
+type CompletionRequest struct {
+	Model    string
+	Messages []ChatMessage
+	// Raw is the whole decoded request body
+	Raw map[string]any
+}
+
+// ChatMessage is a message of a chat completion request.
+// Text parts of multipart contents are joined into Content.
+type ChatMessage struct {
+	Role    string
+	Content string
+	// ToolCallID is set for "tool" messages
+	ToolCallID string
+}
+
+// ToolCall is a function call the Server replies with, see QueueToolCalls
+type ToolCall struct {
+	Name string
+	// Arguments is the JSON-encoded arguments object
+	Arguments string
+}
+
+// reply is a queued chat completion choice
+type reply struct {
+	content   string
+	refusal   string
+	toolCalls []ToolCall
+}
+
+// QueueCompletion makes the next chat completions reply with `contents`, one per request
+func (s *Server) QueueCompletion(contents ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, content := range contents {
+		s.completions = append(s.completions, reply{content: content})
+	}
+}
+
+// QueueToolCalls makes the next chat completion request `calls` in parallel instead of answering
+func (s *Server) QueueToolCalls(calls ...ToolCall) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completions = append(s.completions, reply{toolCalls: calls})
+}
+
+// QueueRefusal makes the next chat completion refuse to answer with the explanation `refusal`
+func (s *Server) QueueRefusal(refusal string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completions = append(s.completions, reply{refusal: refusal})
+}
+
+// SetCompletionFunc makes chat completions reply with the result of `f` once the queued replies are used.
+// By default the Server echoes the last user message.
+func (s *Server) SetCompletionFunc(f func(CompletionRequest) string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completionFunc = f
+}
+
+func (s *Server) createCompletion(w http.ResponseWriter, r *http.Request) {
+	var raw map[string]any
+	if !decode(w, r, &raw) {
+		return
+	}
+	request, invalid := parseCompletionRequest(raw)
+	if invalid != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", invalid)
+		return
+	}
+
+	n := 1
+	if v, ok := raw["n"].(float64); ok && v > 0 {
+		n = int(v)
+	}
+	maxTokens, _ := raw["max_completion_tokens"].(float64)
+	if maxTokens == 0 {
+		maxTokens, _ = raw["max_tokens"].(float64)
+	}
+
+	// every choice takes the next queued reply, one word is counted as one token
+	choices := make([]map[string]any, 0, n)
+	completionTokens := 0
+	for i := 0; i < n; i++ {
+		next := s.nextCompletion(request)
+		if len(next.toolCalls) > 0 {
+			choices = append(choices, s.toolCallChoice(i, next.toolCalls))
+			continue
+		}
+		if next.refusal != "" {
+			choices = append(choices, map[string]any{
+				"index": i,
+				"message": map[string]any{
+					"role":    "assistant",
+					"content": nil,
+					"refusal": next.refusal,
+				},
+				"finish_reason": "stop",
+			})
+			continue
+		}
+		content := next.content
+		words := strings.Fields(content)
+		finishReason := "stop"
+		if maxTokens > 0 && len(words) > int(maxTokens) {
+			content = strings.Join(words[:int(maxTokens)], " ")
+			finishReason = "length"
+		}
+		completionTokens += len(strings.Fields(content))
+		choices = append(choices, map[string]any{
+			"index": i,
+			"message": map[string]any{
+				"role":    "assistant",
+				"content": content,
+				"refusal": nil,
+			},
+			"finish_reason": finishReason,
+		})
+	}
+	promptTokens := countTokens(request.Messages)
+	s.mu.Lock()
+	id := s.newID("chatcmpl")
+	s.mu.Unlock()
+
+	usage := map[string]any{
+		"prompt_tokens":         promptTokens,
+		"completion_tokens":     completionTokens,
+		"total_tokens":          promptTokens + completionTokens,
+		"prompt_tokens_details": map[string]any{"cached_tokens": 0},
+		"completion_tokens_details": map[string]any{
+			"reasoning_tokens": 0,
+		},
+	}
+	w.Header().Set("openai-model", request.Model)
+	if stream, _ := raw["stream"].(bool); stream {
+		streamOptions, _ := raw["stream_options"].(map[string]any)
+		if includeUsage, _ := streamOptions["include_usage"].(bool); !includeUsage {
+			usage = nil
+		}
+		writeCompletionStream(w, id, request.Model, choices, usage)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":                 id,
+		"object":             "chat.completion",
+		"created":            time.Now().Unix(),
+		"model":              request.Model,
+		"system_fingerprint": "fp_openaitest",
+		"choices":            choices,
+		"usage":              usage,
+	})
+}
+
+// writeCompletionStream sends the choices as server-sent chat.completion.chunk events:
+// the role, the content word by word, the finish reason and finally the usage when it isn't nil
+func writeCompletionStream(w http.ResponseWriter, id, model string, choices []map[string]any, usage map[string]any) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	created := time.Now().Unix()
+	send := func(choices []map[string]any, usage map[string]any) {
+		data, _ := json.Marshal(map[string]any{
+			"id":                 id,
+			"object":             "chat.completion.chunk",
+			"created":            created,
+			"model":              model,
+			"system_fingerprint": "fp_openaitest",
+			"choices":            choices,
+			"usage":              usage,
+		})
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+
+	for _, choice := range choices {
+		index := choice["index"]
+		message := choice["message"].(map[string]any)
+		if toolCalls, ok := message["tool_calls"].([]map[string]any); ok {
+			// the first fragment of every call carries its ID and name, the second one its arguments
+			for i, call := range toolCalls {
+				function := call["function"].(map[string]any)
+				send([]map[string]any{{"index": index, "delta": map[string]any{"role": "assistant", "tool_calls": []map[string]any{{
+					"index": i, "id": call["id"], "type": "function",
+					"function": map[string]any{"name": function["name"], "arguments": ""},
+				}}}, "finish_reason": nil}}, nil)
+				send([]map[string]any{{"index": index, "delta": map[string]any{"tool_calls": []map[string]any{{
+					"index": i, "function": map[string]any{"arguments": function["arguments"]},
+				}}}, "finish_reason": nil}}, nil)
+			}
+			send([]map[string]any{{"index": index, "delta": map[string]any{}, "finish_reason": choice["finish_reason"]}}, nil)
+			continue
+		}
+		if refusal, ok := message["refusal"].(string); ok {
+			send([]map[string]any{{"index": index, "delta": map[string]any{"role": "assistant", "refusal": refusal}, "finish_reason": nil}}, nil)
+			send([]map[string]any{{"index": index, "delta": map[string]any{}, "finish_reason": choice["finish_reason"]}}, nil)
+			continue
+		}
+		content := message["content"].(string)
+		send([]map[string]any{{"index": index, "delta": map[string]any{"role": "assistant", "content": ""}, "finish_reason": nil}}, nil)
+		for _, piece := range strings.SplitAfter(content, " ") {
+			if piece == "" {
+				continue
+			}
+			send([]map[string]any{{"index": index, "delta": map[string]any{"content": piece}, "finish_reason": nil}}, nil)
+		}
+		send([]map[string]any{{"index": index, "delta": map[string]any{}, "finish_reason": choice["finish_reason"]}}, nil)
+	}
+	if usage != nil {
+		send([]map[string]any{}, usage)
+	}
+	_, _ = io.WriteString(w, "data: [DONE]\n\n")
+}
+
+func (s *Server) nextCompletion(request CompletionRequest) reply {
+	s.mu.Lock()
+	if len(s.completions) > 0 {
+		next := s.completions[0]
+		s.completions = s.completions[1:]
+		s.mu.Unlock()
+		return next
+	}
+	f := s.completionFunc
+	s.mu.Unlock()
+	if f != nil {
+		return reply{content: f(request)}
+	}
+	return reply{content: "You said: " + lastUserMessage(request.Messages)}
+}
+
+func (s *Server) toolCallChoice(index int, calls []ToolCall) map[string]any {
+	toolCalls := make([]map[string]any, 0, len(calls))
+	s.mu.Lock()
+	for _, call := range calls {
+		toolCalls = append(toolCalls, map[string]any{
+			"id":   s.newID("call"),
+			"type": "function",
+			"function": map[string]any{
+				"name":      call.Name,
+				"arguments": call.Arguments,
+			},
+		})
+	}
+	s.mu.Unlock()
+	return map[string]any{
+		"index": index,
+		"message": map[string]any{
+			"role":       "assistant",
+			"content":    nil,
+			"refusal":    nil,
+			"tool_calls": toolCalls,
+		},
+		"finish_reason": "tool_calls",
+	}
+}
+
+// parseCompletionRequest returns the request or the message of the validation error
+func parseCompletionRequest(raw map[string]any) (CompletionRequest, string) {
+	request := CompletionRequest{Raw: raw}
+	request.Model, _ = raw["model"].(string)
+	messages, _ := raw["messages"].([]any)
+	if len(messages) == 0 {
+		return request, "Invalid 'messages': expected a non-empty array of messages."
+	}
+	toolCallIDs := make(map[string]bool)
+	for _, m := range messages {
+		fields, _ := m.(map[string]any)
+		role, _ := fields["role"].(string)
+		toolCallID, _ := fields["tool_call_id"].(string)
+		calls, _ := fields["tool_calls"].([]any)
+		for _, call := range calls {
+			id, _ := call.(map[string]any)["id"].(string)
+			toolCallIDs[id] = true
+		}
+		if role == "tool" && !toolCallIDs[toolCallID] {
+			return request, "Invalid parameter: messages with role 'tool' must be a response to a preceding message with 'tool_calls'."
+		}
+		request.Messages = append(request.Messages, ChatMessage{
+			Role:       role,
+			Content:    textContent(fields["content"]),
+			ToolCallID: toolCallID,
+		})
+	}
+	return request, ""
+}
+
+// textContent flattens a string or an array of content parts into text
+func textContent(content any) string {
+	switch content := content.(type) {
+	case string:
+		return content
+	case []any:
+		var texts []string
+		for _, part := range content {
+			fields, _ := part.(map[string]any)
+			if text, ok := fields["text"].(string); ok {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, "\n")
+	case nil:
+		return ""
+	default:
+		data, _ := json.Marshal(content)
+		return string(data)
+	}
+}
+
+func lastUserMessage(messages []ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// countTokens roughly estimates tokens as words plus a few tokens of per-message overhead
+func countTokens(messages []ChatMessage) int {
+	tokens := 0
+	for _, m := range messages {
+		tokens += len(strings.Fields(m.Content)) + 4
+	}
+	return tokens
+}
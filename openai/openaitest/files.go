@@ -0,0 +1,110 @@
+package openaitest
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// supportedExtensions are the file types accepted for the "assistants" purpose
+var supportedExtensions = map[string]bool{
+	".c": true, ".cpp": true, ".cs": true, ".css": true, ".csv": true, ".doc": true, ".docx": true,
+	".go": true, ".html": true, ".java": true, ".js": true, ".json": true, ".md": true, ".pdf": true,
+	".php": true, ".pptx": true, ".py": true, ".rb": true, ".sh": true, ".tex": true, ".ts": true,
+	".txt": true, ".xlsx": true,
+}
+
+type file struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int    `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	data      []byte
+}
+
+// FileContent returns the content of the uploaded file with `fileID`
+func (s *Server) FileContent(fileID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[fileID]
+	if !ok {
+		return nil, false
+	}
+	return f.data, true
+}
+
+func (s *Server) uploadFile(w http.ResponseWriter, r *http.Request) {
+	purpose := r.FormValue("purpose")
+	upload, header, err := r.FormFile("file")
+	if err != nil || purpose == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Both 'file' and 'purpose' are required.")
+		return
+	}
+	defer upload.Close()
+	data, err := io.ReadAll(upload)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Failed to read the file.")
+		return
+	}
+	if len(data) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "File is empty.")
+		return
+	}
+	if !supportedExtensions[strings.ToLower(filepath.Ext(header.Filename))] {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid file format for purpose 'assistants'.")
+		return
+	}
+
+	s.mu.Lock()
+	f := &file{
+		ID:        s.newID("file"),
+		Object:    "file",
+		Bytes:     len(data),
+		CreatedAt: time.Now().Unix(),
+		Filename:  header.Filename,
+		Purpose:   purpose,
+		data:      data,
+	}
+	s.files[f.ID] = f
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, f)
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	fileID := r.PathValue("file_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[fileID]
+	if !ok {
+		notFound(w, "file", fileID)
+		return
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+func (s *Server) listFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, list(newestFirst(s.files)))
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	fileID := r.PathValue("file_id")
+	s.mu.Lock()
+	_, ok := s.files[fileID]
+	delete(s.files, fileID)
+	s.mu.Unlock()
+	if !ok {
+		notFound(w, "file", fileID)
+		return
+	}
+	writeJSON(w, http.StatusOK, deleted(fileID, "file"))
+}
+
+func deleted(id, object string) map[string]any {
+	return map[string]any{"id": id, "object": object + ".deleted", "deleted": true}
+}
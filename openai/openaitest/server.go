@@ -0,0 +1,265 @@
+// Package openaitest provides an in-memory fake of OpenAI API for tests of code built on openai.OpenAIClient.
+//
+//	server := openaitest.NewServer()
+//	defer server.Close()
+//	client := server.NewClient()
+//	server.QueueCompletion("Hello from the fake!")
+//	response, err := client.CreateCompletion(chatStory)
+package openaitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ilborsch/openai-go/openai"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an httptest.Server emulating chat completions, files, assistants, threads, messages,
+// runs and vector stores of OpenAI API with in-memory state
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	nextID         int
+	requests       []Request
+	injections     []*Injection
+	completions    []reply
+	completionFunc func(CompletionRequest) string
+	runStatuses    []string
+	runReplies     []string
+
+	files        map[string]*file
+	assistants   map[string]*assistant
+	threads      map[string]*thread
+	vectorStores map[string]*vectorStore
+}
+
+// Request is a request received by the Server
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Injection replaces the response of matching requests, f.e. to inject errors or script responses
+type Injection struct {
+	// Method and Path (without the "/v1" prefix, f.e. "/threads/thread_1/runs") select the requests.
+	// Empty values match any request.
+	Method string
+	Path   string
+
+	StatusCode int
+	Header     http.Header
+	// Body is returned as is. When it is empty and StatusCode isn't successful,
+	// an OpenAI error object is built from Type, Code and Message.
+	Body    string
+	Type    string
+	Code    string
+	Message string
+
+	// Times is the number of requests to answer; zero means one
+	Times int
+}
+
+// NewServer starts a new fake OpenAI API server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		files:        make(map[string]*file),
+		assistants:   make(map[string]*assistant),
+		threads:      make(map[string]*thread),
+		vectorStores: make(map[string]*vectorStore),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /chat/completions", s.createCompletion)
+	mux.HandleFunc("POST /files", s.uploadFile)
+	mux.HandleFunc("GET /files", s.listFiles)
+	mux.HandleFunc("GET /files/{file_id}", s.getFile)
+	mux.HandleFunc("DELETE /files/{file_id}", s.deleteFile)
+	mux.HandleFunc("POST /assistants", s.createAssistant)
+	mux.HandleFunc("GET /assistants", s.listAssistants)
+	mux.HandleFunc("GET /assistants/{assistant_id}", s.getAssistant)
+	mux.HandleFunc("POST /assistants/{assistant_id}", s.modifyAssistant)
+	mux.HandleFunc("DELETE /assistants/{assistant_id}", s.deleteAssistant)
+	mux.HandleFunc("POST /threads", s.createThread)
+	mux.HandleFunc("GET /threads/{thread_id}", s.getThread)
+	mux.HandleFunc("DELETE /threads/{thread_id}", s.deleteThread)
+	mux.HandleFunc("POST /threads/{thread_id}/messages", s.addMessage)
+	mux.HandleFunc("GET /threads/{thread_id}/messages", s.listMessages)
+	mux.HandleFunc("POST /threads/{thread_id}/runs", s.createRun)
+	mux.HandleFunc("GET /threads/{thread_id}/runs", s.listRuns)
+	mux.HandleFunc("GET /threads/{thread_id}/runs/{run_id}", s.getRun)
+	mux.HandleFunc("POST /vector_stores", s.createVectorStore)
+	mux.HandleFunc("GET /vector_stores", s.listVectorStores)
+	mux.HandleFunc("GET /vector_stores/{vector_store_id}", s.getVectorStore)
+	mux.HandleFunc("DELETE /vector_stores/{vector_store_id}", s.deleteVectorStore)
+	mux.HandleFunc("POST /vector_stores/{vector_store_id}/files", s.addVectorStoreFile)
+	mux.HandleFunc("GET /vector_stores/{vector_store_id}/files", s.listVectorStoreFiles)
+	mux.HandleFunc("DELETE /vector_stores/{vector_store_id}/files/{file_id}", s.deleteVectorStoreFile)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// NewClient returns an OpenAI client talking to the Server. `opts` are applied after the base URL.
+func (s *Server) NewClient(opts ...openai.Option) *openai.OpenAI {
+	opts = append([]openai.Option{openai.WithBaseURL(s.URL + "/v1"), openai.WithHTTPClient(s.Client())}, opts...)
+	return openai.New("sk-openaitest", opts...)
+}
+
+// Inject makes the Server answer matching requests with the injection instead of the emulated endpoint
+func (s *Server) Inject(injection Injection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if injection.Times <= 0 {
+		injection.Times = 1
+	}
+	s.injections = append(s.injections, &injection)
+}
+
+// Requests returns all requests received by the Server so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// middleware strips the "/v1" prefix, records the request, checks authentication and applies injections
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/v1")
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		requestID := fmt.Sprintf("req_%d", len(s.requests))
+		injection := s.injection(r)
+		s.mu.Unlock()
+
+		w.Header().Set("x-request-id", requestID)
+		if injection != nil {
+			injection.write(w)
+			return
+		}
+		if r.Header.Get("Authorization") == "" && r.Header.Get("api-key") == "" {
+			writeError(w, http.StatusUnauthorized, "invalid_request_error", "", "You didn't provide an API key.")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) injection(r *http.Request) *Injection {
+	for i, injection := range s.injections {
+		if injection.Method != "" && injection.Method != r.Method || injection.Path != "" && injection.Path != r.URL.Path {
+			continue
+		}
+		injection.Times--
+		if injection.Times == 0 {
+			s.injections = append(s.injections[:i], s.injections[i+1:]...)
+		}
+		return injection
+	}
+	return nil
+}
+
+func (i *Injection) write(w http.ResponseWriter) {
+	for key, values := range i.Header {
+		w.Header()[key] = values
+	}
+	statusCode := i.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	if i.Body == "" && statusCode >= http.StatusBadRequest {
+		writeError(w, statusCode, i.Type, i.Code, i.Message)
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(statusCode)
+	_, _ = io.WriteString(w, i.Body)
+}
+
+// newID returns a unique object ID with `prefix`, f.e. "asst_3"
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s_%d", prefix, s.nextID)
+}
+
+// newestFirst returns `objects` ordered by creation, newest first
+func newestFirst[T any](objects map[string]*T) []*T {
+	ids := make([]string, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return idNumber(ids[i]) > idNumber(ids[j])
+	})
+	sorted := make([]*T, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, objects[id])
+	}
+	return sorted
+}
+
+// idNumber returns the counter part of an ID created by newID
+func idNumber(id string) int {
+	n, _ := strconv.Atoi(id[strings.LastIndex(id, "_")+1:])
+	return n
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, errType, code, message string) {
+	if errType == "" {
+		errType = "invalid_request_error"
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	var errCode any
+	if code != "" {
+		errCode = code
+	}
+	writeJSON(w, statusCode, map[string]any{
+		"error": map[string]any{
+			"message": message,
+			"type":    errType,
+			"param":   nil,
+			"code":    errCode,
+		},
+	})
+}
+
+func notFound(w http.ResponseWriter, object, id string) {
+	writeError(w, http.StatusNotFound, "invalid_request_error", "", fmt.Sprintf("No %s found with id '%s'.", object, id))
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "We could not parse the JSON body of your request.")
+		return false
+	}
+	return true
+}
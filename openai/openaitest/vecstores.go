@@ -0,0 +1,142 @@
+package openaitest
+
+import (
+	"net/http"
+	"time"
+)
+
+type vectorStore struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+	fileIDs   []string
+}
+
+func (s *Server) createVectorStore(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Name string `json:"name"`
+	}
+	if !decode(w, r, &payload) {
+		return
+	}
+	s.mu.Lock()
+	store := &vectorStore{
+		ID:        s.newID("vs"),
+		Object:    "vector_store",
+		Name:      payload.Name,
+		Status:    "completed",
+		CreatedAt: time.Now().Unix(),
+	}
+	s.vectorStores[store.ID] = store
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, store)
+}
+
+func (s *Server) getVectorStore(w http.ResponseWriter, r *http.Request) {
+	storeID := r.PathValue("vector_store_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.vectorStores[storeID]
+	if !ok {
+		notFound(w, "vector store", storeID)
+		return
+	}
+	writeJSON(w, http.StatusOK, store)
+}
+
+func (s *Server) listVectorStores(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, list(newestFirst(s.vectorStores)))
+}
+
+func (s *Server) deleteVectorStore(w http.ResponseWriter, r *http.Request) {
+	storeID := r.PathValue("vector_store_id")
+	s.mu.Lock()
+	_, ok := s.vectorStores[storeID]
+	delete(s.vectorStores, storeID)
+	s.mu.Unlock()
+	if !ok {
+		notFound(w, "vector store", storeID)
+		return
+	}
+	writeJSON(w, http.StatusOK, deleted(storeID, "vector_store"))
+}
+
+func (s *Server) addVectorStoreFile(w http.ResponseWriter, r *http.Request) {
+	storeID := r.PathValue("vector_store_id")
+	var payload struct {
+		FileID string `json:"file_id"`
+	}
+	if !decode(w, r, &payload) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.vectorStores[storeID]
+	if !ok {
+		notFound(w, "vector store", storeID)
+		return
+	}
+	if _, ok := s.files[payload.FileID]; !ok {
+		notFound(w, "file", payload.FileID)
+		return
+	}
+	store.fileIDs = append(store.fileIDs, payload.FileID)
+	writeJSON(w, http.StatusOK, vectorStoreFile(storeID, payload.FileID))
+}
+
+func (s *Server) listVectorStoreFiles(w http.ResponseWriter, r *http.Request) {
+	storeID := r.PathValue("vector_store_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.vectorStores[storeID]
+	if !ok {
+		notFound(w, "vector store", storeID)
+		return
+	}
+	data := make([]map[string]any, 0, len(store.fileIDs))
+	for _, fileID := range store.fileIDs {
+		data = append(data, vectorStoreFile(storeID, fileID))
+	}
+	writeJSON(w, http.StatusOK, list(data))
+}
+
+func (s *Server) deleteVectorStoreFile(w http.ResponseWriter, r *http.Request) {
+	storeID, fileID := r.PathValue("vector_store_id"), r.PathValue("file_id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.vectorStores[storeID]
+	if !ok {
+		notFound(w, "vector store", storeID)
+		return
+	}
+	for i, id := range store.fileIDs {
+		if id == fileID {
+			store.fileIDs = append(store.fileIDs[:i], store.fileIDs[i+1:]...)
+			writeJSON(w, http.StatusOK, deleted(fileID, "vector_store.file"))
+			return
+		}
+	}
+	notFound(w, "file", fileID)
+}
+
+func vectorStoreFile(storeID, fileID string) map[string]any {
+	return map[string]any{
+		"id":              fileID,
+		"object":          "vector_store.file",
+		"vector_store_id": storeID,
+		"status":          "completed",
+	}
+}
+
+func list[T any](data []T) map[string]any {
+	return map[string]any{
+		"object":   "list",
+		"data":     data,
+		"has_more": false,
+	}
+}
@@ -0,0 +1,206 @@
+package openai
+
+import (
+	"fmt"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type (
+	// RetryPolicy describes when and how often failed requests are retried. See WithRetryPolicy.
+	RetryPolicy = transport.RetryPolicy
+	// RetryEvent is passed to RetryPolicy.OnRetry before every retry
+	RetryEvent = transport.RetryEvent
+	// RateLimiter holds callers back while the rate limits reported by OpenAI API are exhausted.
+	// See WithRateLimiter.
+	RateLimiter = transport.RateLimiter
+	// Middleware wraps every request sent by the client. See WithMiddleware.
+	Middleware = transport.Middleware
+	// Handler sends a Call to OpenAI API and returns the response
+	Handler = transport.Handler
+	// Call describes a single attempt to send a request: the endpoint name (f.e. "threads.runs.create"),
+	// the attempt number and the outgoing *http.Request
+	Call = transport.Call
+)
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts with a jittered backoff starting at 500ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     8 * time.Second,
+	}
+}
+
+// Option configures the client returned by New
+type Option func(*options)
+
+type options struct {
+	apiKey       string
+	credentials  CredentialProvider
+	organization string
+	project      string
+	baseURL      string
+	httpClient   *http.Client
+	timeout      time.Duration
+	headers      http.Header
+	retry        *RetryPolicy
+	limiter      *RateLimiter
+	middleware   []Middleware
+	logging      transport.Logging
+}
+
+// WithAPIKey sets the API key sent with every request. Required by NewClient unless WithCredentials is passed.
+func WithAPIKey(apiKey string) Option {
+	return func(o *options) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithBaseURL points every subdomain client at `baseURL` instead of https://api.openai.com/v1.
+// Useful for local mock servers, corporate proxies and OpenAI-compatible gateways.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithCredentials makes the client ask `provider` for the API key of every request,
+// f.e. FileKey to pick up rotated keys or NewKeyPool to fail over between several keys.
+// The key passed to New is ignored and may be empty.
+func WithCredentials(provider CredentialProvider) Option {
+	return func(o *options) {
+		o.credentials = provider
+	}
+}
+
+// WithOrganization sends the `OpenAI-Organization` header with every request,
+// so that the usage is billed to the organization `organizationID`
+func WithOrganization(organizationID string) Option {
+	return func(o *options) {
+		o.organization = organizationID
+	}
+}
+
+// WithProject sends the `OpenAI-Project` header with every request,
+// so that the usage lands on the project `projectID` instead of the default one
+func WithProject(projectID string) Option {
+	return func(o *options) {
+		o.project = projectID
+	}
+}
+
+// WithHTTPClient makes every subdomain client send requests with `client`,
+// so that a single pooled transport is reused across the library.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithTimeout limits the time of a single request, including reading the response body.
+// The http.Client passed to WithHTTPClient is copied and is not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithDefaultHeaders adds `headers` to every request sent by the client.
+// Headers set by the library itself (f.e. Content-Type) take precedence.
+func WithDefaultHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		for k, v := range headers {
+			o.headers.Set(k, v)
+		}
+	}
+}
+
+// WithRetryPolicy enables automatic retries of rate limited and transiently failed requests
+// for every subdomain client. Retries are disabled by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// WithRateLimiter makes every subdomain client wait for `limiter` before sending a request.
+// The limiter learns the remaining requests and tokens from `x-ratelimit-*` response headers
+// and blocks (respecting the request context) calls that would otherwise be rejected with 429.
+// Pass the same limiter to several clients to share the organization limits between them.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithMiddleware adds middlewares observing or modifying the traffic of every subdomain client,
+// f.e. for audit logging, metrics or tenant headers. The first middleware is the outermost one.
+// Middlewares run once per attempt, so retried requests pass through them again.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithLogger makes every subdomain client log request start and finish, endpoint, status, latency,
+// retry attempts and OpenAI request IDs to `logger`. Request headers are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logging.Logger = logger
+	}
+}
+
+// WithLogLevel sets the level of request start and successful finish records (slog.LevelDebug by default).
+// Failed requests are always logged with slog.LevelWarn and retries with slog.LevelInfo.
+func WithLogLevel(level slog.Level) Option {
+	return func(o *options) {
+		o.logging.Level = level
+	}
+}
+
+// WithBodyLogging adds request and response bodies truncated to `maxBytes` to the log records.
+// The API key is replaced with "[REDACTED]" if it appears in a body.
+func WithBodyLogging(maxBytes int) Option {
+	return func(o *options) {
+		o.logging.MaxBodyBytes = maxBytes
+	}
+}
+
+func (o *options) validate() error {
+	if o.apiKey == "" && o.credentials == nil {
+		return ErrMissingAPIKey
+	}
+	if o.baseURL != "" {
+		u, err := url.Parse(o.baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("openai: invalid base URL %q: expected an absolute http(s) URL", o.baseURL)
+		}
+	}
+	if o.timeout < 0 {
+		return fmt.Errorf("openai: invalid timeout %s: must not be negative", o.timeout)
+	}
+	if o.retry != nil && o.retry.MaxAttempts < 0 {
+		return fmt.Errorf("openai: invalid retry policy: negative MaxAttempts %d", o.retry.MaxAttempts)
+	}
+	return nil
+}
+
+func (o *options) httpClientOrDefault() *http.Client {
+	client := o.httpClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	if o.timeout > 0 {
+		withTimeout := *client
+		withTimeout.Timeout = o.timeout
+		client = &withTimeout
+	}
+	return client
+}
@@ -0,0 +1,77 @@
+package openai
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai/internal/transport"
+	"net/http"
+)
+
+type (
+	// ResponseMeta describes the HTTP response of a call: status, headers, request ID,
+	// processing time, served model and rate limit state. See RequestMeta.
+	ResponseMeta = transport.ResponseMeta
+	// RateLimitInfo is the rate limit state reported by the `x-ratelimit-*` headers
+	RateLimitInfo = transport.RateLimitInfo
+)
+
+// RequestOption overrides the client configuration for a single call.
+// Attach request options to the context passed to any `...Context` method with WithRequestOptions.
+type RequestOption = transport.CallOption
+
+// WithRequestOptions returns a copy of ctx carrying `opts`.
+// Options already attached to ctx are kept unless overridden.
+//
+//	ctx = openai.WithRequestOptions(ctx, openai.RequestProject("proj_abc"))
+//	runID, err := client.CreateRunContext(ctx, threadID, assistantID)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	return transport.WithCallOptions(ctx, opts...)
+}
+
+// RequestOrganization overrides the `OpenAI-Organization` header set by WithOrganization
+func RequestOrganization(organizationID string) RequestOption {
+	return func(o *transport.CallOptions) {
+		o.Organization = organizationID
+	}
+}
+
+// RequestProject overrides the `OpenAI-Project` header set by WithProject
+func RequestProject(projectID string) RequestOption {
+	return func(o *transport.CallOptions) {
+		o.Project = projectID
+	}
+}
+
+// RequestHeader sets an additional header, replacing the value set by the library or WithDefaultHeaders
+func RequestHeader(key, value string) RequestOption {
+	return func(o *transport.CallOptions) {
+		if o.Headers == nil {
+			o.Headers = make(http.Header)
+		}
+		o.Headers.Set(key, value)
+	}
+}
+
+// RequestMeta fills `meta` with the metadata of the response, including unsuccessful ones.
+// When the context is used for several calls, `meta` describes the last of them.
+//
+//	var meta openai.ResponseMeta
+//	ctx = openai.WithRequestOptions(ctx, openai.RequestMeta(&meta))
+//	assistantID, err := client.CreateAssistantContext(ctx, name, instructions, vsID, nil)
+//	log.Println(meta.RequestID, meta.ProcessingTime)
+func RequestMeta(meta *ResponseMeta) RequestOption {
+	return func(o *transport.CallOptions) {
+		o.Meta = meta
+	}
+}
+
+// RequestIdempotencyKey sets the `Idempotency-Key` header of a create operation (f.e. CreateRunContext),
+// replacing the key the library generates for every call. Reuse the key when repeating a call
+// which timed out, so that OpenAI API doesn't create a duplicate.
+// The key belongs to the first create operation made with the context: any other create operation
+// made with it, including the same one with a different payload (f.e. another message added to the same thread),
+// fails with ErrIdempotencyKeyReused instead of getting the result of the first one.
+func RequestIdempotencyKey(key string) RequestOption {
+	return func(o *transport.CallOptions) {
+		o.IdempotencyKey = &transport.IdempotencyKey{Key: key}
+	}
+}
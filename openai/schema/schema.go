@@ -0,0 +1,362 @@
+// Package schema generates JSON Schema from Go types for chat tools, assistant function tools
+// and structured outputs.
+//
+// Struct fields are named after their `json` tags and documented with `description` tags.
+// The `enum` tag lists the allowed values separated by commas:
+//
+//	type Forecast struct {
+//		City  string    `json:"city" description:"City name in English"`
+//		Unit  string    `json:"unit" enum:"celsius,fahrenheit"`
+//		Days  []Day     `json:"days"`
+//		Notes *string   `json:"notes"`
+//		At    time.Time `json:"at"`
+//	}
+//
+// To meet the requirements of OpenAI strict mode, every property is listed as required
+// and objects don't allow additional properties. Optional fields (pointers and fields tagged with
+// `omitempty`) are made nullable instead, so the model sends null for missing values.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema object
+type Schema struct {
+	// Type is a JSON type name or, for nullable values, a list of them (f.e. ["string", "null"])
+	Type        any       `json:"type,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Enum        []any     `json:"enum,omitempty"`
+	Format      string    `json:"format,omitempty"`
+	Items       *Schema   `json:"items,omitempty"`
+	AnyOf       []*Schema `json:"anyOf,omitempty"`
+	Ref         string    `json:"$ref,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for structs and the schema of the values for maps
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	// strict is false when the type can't be described in strict mode, f.e. maps and interfaces
+	strict bool
+}
+
+// ErrUnsupportedType is returned for types without a JSON representation, f.e. channels and functions
+var ErrUnsupportedType = errors.New("schema: unsupported type")
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	// textMarshalerType keys are encoded as strings by encoding/json
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// For returns the JSON Schema of T
+func For[T any]() (*Schema, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect returns the JSON Schema of the type `t`. Recursive structs are described with `$defs`.
+func Reflect(t reflect.Type) (*Schema, error) {
+	g := generator{
+		defs:    make(map[reflect.Type]string),
+		visited: make(map[reflect.Type]bool),
+		root:    t,
+	}
+	s, err := g.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.defSchemas) > 0 {
+		s.Defs = g.defSchemas
+	}
+	return s, nil
+}
+
+// Strict reports whether the schema meets the requirements of OpenAI strict mode,
+// so it may be used with `strict: true` in tools and response formats
+func (s *Schema) Strict() bool {
+	return s.strict
+}
+
+type generator struct {
+	// defs names the struct types referenced from `$defs`
+	defs       map[reflect.Type]string
+	defSchemas map[string]*Schema
+	// visited are the struct types being described, to detect recursion
+	visited map[reflect.Type]bool
+	root    reflect.Type
+}
+
+func (g *generator) schema(t reflect.Type) (*Schema, error) {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", strict: true}, nil
+	case t == rawJSONType:
+		return &Schema{}, nil
+	case t.Kind() != reflect.Pointer && t.Implements(marshalerType):
+		// the JSON form of custom marshalers is unknown
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", strict: true}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", strict: true}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", strict: true}, nil
+	case reflect.String:
+		return &Schema{Type: "string", strict: true}, nil
+	case reflect.Pointer:
+		elem, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings, but byte arrays as arrays of numbers
+			return &Schema{Type: "string", strict: true}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items, strict: items.strict}, nil
+	case reflect.Map:
+		if key := t.Key(); key.Kind() != reflect.String && !key.Implements(textMarshalerType) &&
+			(key.Kind() < reflect.Int || key.Kind() > reflect.Uint64) {
+			return nil, fmt.Errorf("%w: map key %s", ErrUnsupportedType, t.Key())
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		// strict mode requires `additionalProperties: false`, so maps can't be strict
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func (g *generator) object(t reflect.Type) (*Schema, error) {
+	if g.visited[t] {
+		return g.ref(t), nil
+	}
+	g.visited[t] = true
+	defer delete(g.visited, t)
+
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		Required:             []string{},
+		AdditionalProperties: false,
+		strict:               true,
+	}
+	if err := g.fields(t, s); err != nil {
+		return nil, err
+	}
+
+	name, recursive := g.defs[t]
+	if !recursive {
+		return s, nil
+	}
+	if t == g.root {
+		// the root references itself as "#"
+		return s, nil
+	}
+	g.defSchemas[name] = s
+	return &Schema{Ref: "#/$defs/" + name, strict: s.strict}, nil
+}
+
+// ref returns a reference to the struct type `t`, which is described in `$defs` unless it is the root
+func (g *generator) ref(t reflect.Type) *Schema {
+	if t == g.root {
+		g.defs[t] = ""
+		return &Schema{Ref: "#", strict: true}
+	}
+	name, ok := g.defs[t]
+	if !ok {
+		name = t.Name()
+		if name == "" {
+			name = "Object"
+		}
+		// keep names unique for equally named types of different packages
+		for i := 2; g.defSchemas[name] != nil || g.hasDefName(name); i++ {
+			name = t.Name() + strconv.Itoa(i)
+		}
+		g.defs[t] = name
+		if g.defSchemas == nil {
+			g.defSchemas = make(map[string]*Schema)
+		}
+	}
+	return &Schema{Ref: "#/$defs/" + name, strict: true}
+}
+
+func (g *generator) hasDefName(name string) bool {
+	for _, n := range g.defs {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fields adds the properties of the struct `t` to `s`, flattening embedded structs like encoding/json
+func (g *generator) fields(t reflect.Type, s *Schema) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.fields(embedded, s); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := g.schema(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			property = nullable(property)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			property = describe(property, description)
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values, err := enumValues(field.Type, enum)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			property = withEnum(property, values)
+		}
+
+		if _, ok := s.Properties[name]; !ok {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = property
+		s.strict = s.strict && property.strict
+	}
+	return nil
+}
+
+// nullable allows null in addition to the values of `s`
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		if s.Properties == nil && s.Items == nil && s.AdditionalProperties == nil {
+			copied := *s
+			copied.Type = []string{typ, "null"}
+			return &copied
+		}
+	case []string:
+		return s
+	case nil:
+		if s.Ref == "" && s.AnyOf == nil {
+			// {} already allows null
+			return s
+		}
+	}
+	return &Schema{
+		AnyOf:  []*Schema{s, {Type: "null", strict: true}},
+		strict: s.strict,
+	}
+}
+
+// withEnum restricts the values of `s` to `values`. The enum goes to the items of arrays
+// and allows null as well when `s` is nullable.
+func withEnum(s *Schema, values []any) *Schema {
+	copied := *s
+	switch {
+	case copied.Items != nil:
+		copied.Items = withEnum(copied.Items, values)
+	case len(copied.AnyOf) > 0:
+		copied.AnyOf = make([]*Schema, len(s.AnyOf))
+		for i, option := range s.AnyOf {
+			if option.Type == "null" {
+				copied.AnyOf[i] = option
+				continue
+			}
+			copied.AnyOf[i] = withEnum(option, values)
+		}
+	default:
+		copied.Enum = values
+		if typ, ok := copied.Type.([]string); ok && len(typ) == 2 && typ[1] == "null" {
+			copied.Enum = append(append([]any(nil), values...), nil)
+		}
+	}
+	return &copied
+}
+
+// describe sets the description of `s`, which is moved out of a `$ref` (that can't have siblings)
+func describe(s *Schema, description string) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s}, Description: description, strict: s.strict}
+	}
+	copied := *s
+	copied.Description = description
+	return &copied
+}
+
+// enumValues parses the comma-separated `enum` tag into values of the field type
+func enumValues(t reflect.Type, enum string) ([]any, error) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	var values []any
+	for _, value := range strings.Split(enum, ",") {
+		value = strings.TrimSpace(value)
+		switch t.Kind() {
+		case reflect.String:
+			values = append(values, value)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer enum value %q", value)
+			}
+			values = append(values, n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number enum value %q", value)
+			}
+			values = append(values, n)
+		default:
+			return nil, fmt.Errorf("enum is not supported for %s", t)
+		}
+	}
+	return values, nil
+}
@@ -1,8 +1,11 @@
 package assistants
 
 import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
 	"github.com/ilborsch/openai-go/openai/assistants"
 	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
@@ -95,3 +98,13 @@ func TestLatestAssistantResponse_InvalidThreadID(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, response)
 }
+
+func TestLatestAssistantResponseContext_Cancelled(t *testing.T) {
+	client := openai.New("test-key", openai.WithBaseURL("http://127.0.0.1:0"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := client.LatestAssistantResponseContext(ctx, "thread_123")
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, response)
+}
@@ -0,0 +1,67 @@
+package assistants
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAssistants_ResourceMethods(t *testing.T) {
+	s := suite.NewFake(t)
+	client := s.Client
+	fileID, err := client.Files.Create("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+
+	storeID, err := client.VectorStores.Create("store")
+	require.NoError(t, err)
+	require.NoError(t, client.VectorStores.AddFile(storeID, fileID))
+	store, err := client.VectorStores.Get(storeID)
+	require.NoError(t, err)
+	assert.Equal(t, "store", store.Name)
+	stores, err := client.VectorStores.List()
+	require.NoError(t, err)
+	require.Len(t, stores.VectorStores, 1)
+
+	assistantID, err := client.Assistants.Create("name", "instructions", storeID, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.Assistants.Update(assistantID, "new instructions", "", 0))
+	assistant, err := client.Assistants.Get(assistantID)
+	require.NoError(t, err)
+	assert.Equal(t, assistantID, assistant.ID)
+	assert.Equal(t, "new instructions", assistant.Instructions)
+	assistantList, err := client.Assistants.List()
+	require.NoError(t, err)
+	require.Len(t, assistantList.Assistants, 1)
+
+	threadID, err := client.Threads.Create()
+	require.NoError(t, err)
+	thread, err := client.Threads.Get(threadID)
+	require.NoError(t, err)
+	assert.Equal(t, threadID, thread.ID)
+	require.NoError(t, client.Messages.Create(threadID, "Hello"))
+
+	firstRunID, err := client.Runs.Create(threadID, assistantID)
+	require.NoError(t, err)
+	secondRunID, err := client.Runs.Create(threadID, assistantID)
+	require.NoError(t, err)
+	run, err := client.Runs.Get(threadID, firstRunID)
+	require.NoError(t, err)
+	assert.Equal(t, firstRunID, run.ID)
+	runList, err := client.Runs.List(threadID)
+	require.NoError(t, err)
+	require.Len(t, runList.Runs, 2)
+	assert.Equal(t, secondRunID, runList.Runs[0].ID)
+
+	messages, err := client.Messages.List(threadID)
+	require.NoError(t, err)
+	assert.Len(t, messages.Data, 3)
+
+	require.NoError(t, client.Threads.Delete(threadID))
+	require.NoError(t, client.Assistants.Delete(assistantID))
+	require.NoError(t, client.VectorStores.DeleteFile(storeID, fileID))
+	require.NoError(t, client.VectorStores.Delete(storeID))
+	_, err = client.Threads.Get(threadID)
+	require.True(t, openai.IsNotFound(err))
+}
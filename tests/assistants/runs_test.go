@@ -1,8 +1,10 @@
 package assistants
 
 import (
+	"github.com/ilborsch/openai-go/openai"
 	"github.com/ilborsch/openai-go/openai/assistants"
 	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -150,3 +152,36 @@ func TestGetRun_InvalidThreadID(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, run)
 }
+
+func TestGetRun_Lifecycle(t *testing.T) {
+	s := suite.NewFake(t)
+	client := s.Client
+	s.Server.SetRunStatuses("queued", "in_progress", "completed")
+	s.Server.QueueRunReply("Sure, I can help.")
+
+	storeID, err := client.CreateVectorStore("store")
+	require.NoError(t, err)
+	assistantID, err := client.CreateAssistant("name", "instructions", storeID, nil)
+	require.NoError(t, err)
+	threadID, err := client.CreateThread()
+	require.NoError(t, err)
+	require.NoError(t, client.AddMessageToThread(threadID, "Can you help me?"))
+
+	runID, err := client.CreateRun(threadID, assistantID)
+	require.NoError(t, err)
+	for _, status := range []string{"in_progress", "completed"} {
+		run, err := client.GetRun(threadID, runID)
+		require.NoError(t, err)
+		assert.Equal(t, status, run.Status)
+		assert.Equal(t, assistantID, run.AssistantID)
+	}
+
+	response, err := client.LatestAssistantResponse(threadID)
+	require.NoError(t, err)
+	assert.Equal(t, "Sure, I can help.", response)
+
+	_, err = client.GetRun("thread_missing", runID)
+	require.True(t, openai.IsNotFound(err))
+	_, err = client.CreateRun(threadID, "asst_missing")
+	require.True(t, openai.IsNotFound(err))
+}
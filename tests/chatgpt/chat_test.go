@@ -0,0 +1,176 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// requestBody decodes the body of the `i`-th request received by `server`
+func requestBody(t *testing.T, server *openaitest.Server, i int) map[string]any {
+	t.Helper()
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(server.Requests()[i].Body, &body))
+	return body
+}
+
+func TestCreateChatCompletion_Parameters(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueCompletion("Hello!", "Hi!")
+
+	response, err := client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Model:               "gpt-4o",
+		Messages:            []message.Message{message.NewUserMessage("Hi")},
+		Temperature:         chatgpt.Ptr(0.0),
+		TopP:                chatgpt.Ptr(0.9),
+		MaxCompletionTokens: chatgpt.Ptr(256),
+		Stop:                []string{"\n\n"},
+		PresencePenalty:     chatgpt.Ptr(0.5),
+		FrequencyPenalty:    chatgpt.Ptr(-0.5),
+		Seed:                chatgpt.Ptr[int64](42),
+		LogitBias:           map[string]int{"50256": -100},
+		N:                   chatgpt.Ptr(2),
+		User:                "user_123",
+		ServiceTier:         "flex",
+		ReasoningEffort:     "low",
+		Store:               chatgpt.Ptr(false),
+		Metadata:            map[string]string{"feature": "chat"},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Choices, 2)
+	assert.Equal(t, "Hello!", response.Choices[0].Message.Content)
+
+	assert.Equal(t, map[string]any{
+		"model":                 "gpt-4o",
+		"messages":              []any{map[string]any{"role": "user", "content": "Hi"}},
+		"temperature":           0.0,
+		"top_p":                 0.9,
+		"max_completion_tokens": 256.0,
+		"stop":                  []any{"\n\n"},
+		"presence_penalty":      0.5,
+		"frequency_penalty":     -0.5,
+		"seed":                  42.0,
+		"logit_bias":            map[string]any{"50256": -100.0},
+		"n":                     2.0,
+		"user":                  "user_123",
+		"service_tier":          "flex",
+		"reasoning_effort":      "low",
+		"store":                 false,
+		"metadata":              map[string]any{"feature": "chat"},
+	}, requestBody(t, server, 0))
+}
+
+func TestCreateChatCompletion_Defaults(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueCompletion("Hello!")
+
+	content, err := client.Chat.CreateCompletion([]message.Message{message.NewUserMessage("Hi")})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello!", content)
+	// unset optional parameters are omitted, so OpenAI API defaults apply
+	assert.Equal(t, map[string]any{
+		"model":    chatgpt.DefaultModel,
+		"messages": []any{map[string]any{"role": "user", "content": "Hi"}},
+	}, requestBody(t, server, 0))
+}
+
+func TestCreateChatCompletion_Response(t *testing.T) {
+	s := suite.NewFake(t)
+	s.Server.Inject(openaitest.Injection{Body: `{
+			"id": "chatcmpl_123",
+			"object": "chat.completion",
+			"created": 1741569952,
+			"model": "gpt-4o-2024-08-06",
+			"system_fingerprint": "fp_123",
+			"service_tier": "default",
+			"choices": [
+				{"index": 0, "message": {"role": "assistant", "content": "A long"}, "finish_reason": "length"},
+				{"index": 1, "message": {"role": "assistant", "content": null, "refusal": "I can't help"}, "finish_reason": "content_filter"}
+			],
+			"usage": {
+				"prompt_tokens": 19, "completion_tokens": 10, "total_tokens": 29,
+				"prompt_tokens_details": {"cached_tokens": 8, "audio_tokens": 0},
+				"completion_tokens_details": {"reasoning_tokens": 4, "audio_tokens": 0, "accepted_prediction_tokens": 0, "rejected_prediction_tokens": 0}
+			}
+	}`})
+	client := s.Client
+
+	completion, err := client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+		N:        chatgpt.Ptr(2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chatcmpl_123", completion.ID)
+	assert.Equal(t, int64(1741569952), completion.Created)
+	assert.Equal(t, "gpt-4o-2024-08-06", completion.Model)
+	assert.Equal(t, "fp_123", completion.SystemFingerprint)
+	assert.Equal(t, "default", completion.ServiceTier)
+	assert.Equal(t, "A long", completion.Content())
+	require.Len(t, completion.Choices, 2)
+	assert.Equal(t, chatgpt.FinishReasonLength, completion.Choices[0].FinishReason)
+	assert.Equal(t, chatgpt.FinishReasonContentFilter, completion.Choices[1].FinishReason)
+	assert.Equal(t, "I can't help", completion.Choices[1].Message.Refusal)
+	assert.Equal(t, chatgpt.Usage{
+		PromptTokens:            19,
+		CompletionTokens:        10,
+		TotalTokens:             29,
+		PromptTokensDetails:     chatgpt.PromptTokensDetails{CachedTokens: 8},
+		CompletionTokensDetails: chatgpt.CompletionTokensDetails{ReasoningTokens: 4},
+	}, completion.Usage)
+}
+
+func TestFakeServer_ChatCompletionChoices(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueCompletion("first", "second answer is too long")
+
+	completion, err := client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages:            []message.Message{message.NewUserMessage("Hi")},
+		N:                   chatgpt.Ptr(2),
+		MaxCompletionTokens: chatgpt.Ptr(3),
+	})
+	require.NoError(t, err)
+	require.Len(t, completion.Choices, 2)
+	assert.Equal(t, "first", completion.Choices[0].Message.Content)
+	assert.Equal(t, chatgpt.FinishReasonStop, completion.Choices[0].FinishReason)
+	assert.Equal(t, "second answer is", completion.Choices[1].Message.Content)
+	assert.Equal(t, chatgpt.FinishReasonLength, completion.Choices[1].FinishReason)
+	assert.Equal(t, 4, completion.Usage.CompletionTokens)
+}
+
+func TestChatCompletion_Logprobs(t *testing.T) {
+	var completion chatgpt.ChatCompletion
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": "Hi"},
+			"finish_reason": "stop",
+			"logprobs": {"content": [{
+				"token": "Hi", "logprob": -0.1, "bytes": [72, 105],
+				"top_logprobs": [{"token": "Hi", "logprob": -0.1, "bytes": [72, 105]}, {"token": "Hey", "logprob": -2.5, "bytes": null}]
+			}], "refusal": null}
+		}]
+	}`), &completion))
+
+	require.NotNil(t, completion.Choices[0].Logprobs)
+	assert.Equal(t, []chatgpt.TokenLogprob{{
+		Token:   "Hi",
+		Logprob: -0.1,
+		Bytes:   []int{72, 105},
+		TopLogprobs: []chatgpt.TopLogprob{
+			{Token: "Hi", Logprob: -0.1, Bytes: []int{72, 105}},
+			{Token: "Hey", Logprob: -2.5},
+		},
+	}}, completion.Choices[0].Logprobs.Content)
+}
@@ -39,3 +39,10 @@ func TestChatGPTCompletion_Error(t *testing.T) {
 	require.Error(t, err)
 	assert.Empty(t, response)
 }
+
+func TestChatCreate_ResourceMethod(t *testing.T) {
+	s := suite.NewFake(t)
+	response, err := s.Client.Chat.Create([]message.Message{message.NewUserMessage("Hi")})
+	require.NoError(t, err)
+	assert.Equal(t, "You said: Hi", response)
+}
@@ -0,0 +1,120 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestUserMessage_StringContent(t *testing.T) {
+	data, err := json.Marshal(message.NewUserMessage("Hello"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role": "user", "content": "Hello"}`, string(data))
+}
+
+func TestUserMessage_Parts(t *testing.T) {
+	image, err := message.NewImagePartFromReader(strings.NewReader(string(pngHeader)), message.ImageDetailLow)
+	require.NoError(t, err)
+	msg := message.NewUserMessageParts(
+		message.NewTextPart("What is on the image?"),
+		image,
+		message.NewImageURLPart("https://example.com/cat.jpg", ""),
+		message.NewFilePart("file-123"),
+	)
+	assert.Equal(t, "What is on the image?", msg.Message())
+
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role": "user", "content": [
+		{"type": "text", "text": "What is on the image?"},
+		{"type": "image_url", "image_url": {"url": "data:image/png;base64,`+base64.StdEncoding.EncodeToString(pngHeader)+`", "detail": "low"}},
+		{"type": "image_url", "image_url": {"url": "https://example.com/cat.jpg"}},
+		{"type": "file", "file": {"file_id": "file-123"}}
+	]}`, string(data))
+
+	var decoded message.UserMessage
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *msg, decoded)
+}
+
+func TestUserMessage_ContentWithParts(t *testing.T) {
+	msg := message.NewUserMessage("Describe it")
+	msg.Parts = []message.ContentPart{message.NewImageURLPart("https://example.com/cat.jpg", message.ImageDetailHigh)}
+
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role": "user", "content": [
+		{"type": "text", "text": "Describe it"},
+		{"type": "image_url", "image_url": {"url": "https://example.com/cat.jpg", "detail": "high"}}
+	]}`, string(data))
+}
+
+func TestContentParts_FromFiles(t *testing.T) {
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "chart")
+	require.NoError(t, os.WriteFile(imagePath, pngHeader, 0o600))
+	wav := []byte("RIFF\x24\x00\x00\x00WAVEfmt ")
+	audioPath := filepath.Join(dir, "voice.wav")
+	require.NoError(t, os.WriteFile(audioPath, wav, 0o600))
+	rawAudioPath := filepath.Join(dir, "voice.mp3")
+	require.NoError(t, os.WriteFile(rawAudioPath, []byte{0xff, 0x00}, 0o600))
+	pdfPath := filepath.Join(dir, "report.pdf")
+	require.NoError(t, os.WriteFile(pdfPath, []byte("%PDF-1.7\n"), 0o600))
+
+	image, err := message.NewImagePartFromFile(imagePath, "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(image.ImageURL.URL, "data:image/png;base64,"))
+
+	audio, err := message.NewAudioPartFromFile(audioPath)
+	require.NoError(t, err)
+	assert.Equal(t, &message.InputAudio{Data: base64.StdEncoding.EncodeToString(wav), Format: message.AudioFormatWAV}, audio.InputAudio)
+
+	audio, err = message.NewAudioPartFromFile(rawAudioPath)
+	require.NoError(t, err)
+	assert.Equal(t, message.AudioFormatMP3, audio.InputAudio.Format)
+
+	file, err := message.NewFilePartFromFile(pdfPath)
+	require.NoError(t, err)
+	assert.Equal(t, &message.File{
+		Filename: "report.pdf",
+		FileData: "data:application/pdf;base64," + base64.StdEncoding.EncodeToString([]byte("%PDF-1.7\n")),
+	}, file.File)
+
+	_, err = message.NewImagePartFromFile(pdfPath, "")
+	assert.ErrorContains(t, err, `unsupported image type "application/pdf"`)
+	_, err = message.NewAudioPartFromReader(strings.NewReader("plain text"), "")
+	assert.ErrorContains(t, err, "unsupported audio type")
+}
+
+func TestCreateCompletion_MultimodalMessage(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+
+	reply, err := s.Client.CreateCompletionContext(context.Background(), []message.Message{
+		message.NewUserMessageParts(
+			message.NewTextPart("What is this?"),
+			message.NewImageURLPart("https://example.com/cat.jpg", message.ImageDetailAuto),
+		),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "You said: What is this?", reply)
+
+	var payload struct {
+		Messages []struct {
+			Content []message.ContentPart `json:"content"`
+		} `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(server.Requests()[0].Body, &payload))
+	require.Len(t, payload.Messages, 1)
+	assert.Equal(t, "https://example.com/cat.jpg", payload.Messages[0].Content[1].ImageURL.URL)
+}
@@ -0,0 +1,181 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+// completerFunc is a chatgpt.ChatCompleter implemented by a function, like the mocks of the library users
+type completerFunc func(ctx context.Context, request chatgpt.ChatCompletionRequest) (chatgpt.ChatCompletion, error)
+
+func (f completerFunc) CreateChatCompletion(ctx context.Context, request chatgpt.ChatCompletionRequest) (chatgpt.ChatCompletion, error) {
+	return f(ctx, request)
+}
+
+func TestToolRunner_Conversation(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueToolCalls(
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Rome"}`},
+	)
+	server.QueueCompletion("Paris is sunny and Rome is rainy")
+
+	// both calls must run at the same time to pass the barrier
+	var started sync.WaitGroup
+	started.Add(2)
+	runner := chatgpt.NewToolRunner(client)
+	runner.Request.Model = "gpt-4o"
+	chatgpt.RegisterTool(runner, "get_weather", "Returns the weather in a city", nil,
+		func(ctx context.Context, args weatherArgs) (any, error) {
+			started.Done()
+			started.Wait()
+			if args.City == "Paris" {
+				return "sunny", nil
+			}
+			return map[string]string{"city": args.City, "weather": "rainy"}, nil
+		})
+
+	done := make(chan struct{})
+	var transcript []message.Message
+	var err error
+	go func() {
+		defer close(done)
+		transcript, err = runner.RunConversation(context.Background(), []message.Message{
+			message.NewUserMessage("Weather in Paris and Rome?"),
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool calls were not executed concurrently")
+	}
+	require.NoError(t, err)
+
+	require.Len(t, transcript, 5)
+	toolCalls := transcript[1].(*message.AssistantMessage).ToolCalls
+	require.Len(t, toolCalls, 2)
+	assert.Equal(t, message.NewToolMessage(toolCalls[0].ID, "sunny"), transcript[2])
+	assert.Equal(t, message.NewToolMessage(toolCalls[1].ID, `{"city":"Rome","weather":"rainy"}`), transcript[3])
+	assert.Equal(t, "Paris is sunny and Rome is rainy", transcript[4].Message())
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(server.Requests()[0].Body, &payload))
+	assert.Equal(t, "gpt-4o", payload["model"])
+	assert.Len(t, payload["tools"], 1)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestToolRunner_FailedCallsAreReported(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueToolCalls(
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":`},
+		openaitest.ToolCall{Name: "get_time", Arguments: `{}`},
+		openaitest.ToolCall{Name: "explode", Arguments: `{}`},
+	)
+	server.QueueCompletion("Sorry, the weather service is down")
+
+	runner := chatgpt.NewToolRunner(client)
+	chatgpt.RegisterTool(runner, "get_weather", "", nil, func(ctx context.Context, args weatherArgs) (any, error) {
+		return nil, errors.New("weather service is down")
+	})
+	chatgpt.RegisterTool(runner, "explode", "", nil, func(ctx context.Context, args struct{}) (any, error) {
+		panic("boom")
+	})
+
+	transcript, err := runner.RunConversation(context.Background(), []message.Message{message.NewUserMessage("Weather?")})
+	require.NoError(t, err)
+	require.Len(t, transcript, 7)
+	assert.JSONEq(t, `{"error": "weather service is down"}`, transcript[2].Message())
+	assert.Contains(t, transcript[3].Message(), "invalid arguments")
+	assert.JSONEq(t, `{"error": "unknown tool \"get_time\""}`, transcript[4].Message())
+	assert.JSONEq(t, `{"error": "panic: boom"}`, transcript[5].Message())
+	assert.Equal(t, "Sorry, the weather service is down", transcript[6].Message())
+}
+
+func TestToolRunner_MaxSteps(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	for i := 0; i < 3; i++ {
+		server.QueueToolCalls(openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Paris"}`})
+	}
+
+	runner := chatgpt.NewToolRunner(client)
+	runner.MaxSteps = 2
+	chatgpt.RegisterTool(runner, "get_weather", "", nil, func(ctx context.Context, args weatherArgs) (any, error) {
+		return "sunny", nil
+	})
+
+	transcript, err := runner.RunConversation(context.Background(), []message.Message{message.NewUserMessage("Weather?")})
+	require.ErrorIs(t, err, chatgpt.ErrMaxSteps)
+	assert.Len(t, transcript, 5)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestToolRunner_GeneratedParameters(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueToolCalls(openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Oslo"}`})
+	server.QueueCompletion("Snowy")
+
+	runner := chatgpt.NewToolRunner(s.Client)
+	chatgpt.RegisterTool(runner, "get_weather", "Returns the weather in a city", nil,
+		func(ctx context.Context, args weatherArgs) (any, error) {
+			return "snow in " + args.City, nil
+		})
+	_, err := runner.RunConversation(context.Background(), []message.Message{message.NewUserMessage("Weather in Oslo?")})
+	require.NoError(t, err)
+
+	var payload struct {
+		Tools []chatgpt.Tool `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(server.Requests()[0].Body, &payload))
+	require.Len(t, payload.Tools, 1)
+	assert.True(t, payload.Tools[0].Function.Strict)
+	parameters, err := json.Marshal(payload.Tools[0].Function.Parameters)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {"city": {"type": "string"}},
+		"required": ["city"],
+		"additionalProperties": false
+	}`, string(parameters))
+}
+
+func TestToolRunner_ChatCompleter(t *testing.T) {
+	s := suite.NewFake(t)
+	s.Server.QueueToolCalls(openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Oslo"}`})
+	s.Server.QueueCompletion("Snowy")
+
+	var calls int
+	runner := chatgpt.NewToolRunner(completerFunc(func(ctx context.Context, request chatgpt.ChatCompletionRequest) (chatgpt.ChatCompletion, error) {
+		calls++
+		return s.Client.CreateChatCompletion(ctx, request)
+	}))
+	chatgpt.RegisterTool(runner, "get_weather", "", nil, func(ctx context.Context, args weatherArgs) (any, error) {
+		return "snow in " + args.City, nil
+	})
+	transcript, err := runner.RunConversation(context.Background(), []message.Message{message.NewUserMessage("Weather in Oslo?")})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "Snowy", transcript[len(transcript)-1].Message())
+}
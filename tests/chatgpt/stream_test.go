@@ -0,0 +1,198 @@
+package chatgpt
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// eventStream is the Injection answering a chat completion with the server-sent events `body`
+func eventStream(body string) openaitest.Injection {
+	return openaitest.Injection{
+		Method: http.MethodPost,
+		Path:   "/chat/completions",
+		Header: http.Header{"Content-Type": {"text/event-stream"}},
+		Body:   body,
+	}
+}
+
+func TestChatCompletionStream_Happy(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueCompletion("Hello there, how are you?")
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content strings.Builder
+	var chunks int
+	var usage *chatgpt.Usage
+	for stream.Next() {
+		chunk := stream.Current()
+		chunks++
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "Hello there, how are you?", content.String())
+	assert.Greater(t, chunks, 3)
+	require.NotNil(t, usage)
+	assert.Equal(t, 5, usage.CompletionTokens)
+	assert.False(t, stream.Next())
+
+	requests := server.Requests()
+	assert.Contains(t, string(requests[0].Body), `"stream":true`)
+	assert.Contains(t, string(requests[0].Body), `"stream_options":{"include_usage":true}`)
+}
+
+func TestChatCompletionStream_UsageChunk(t *testing.T) {
+	s := suite.NewFake(t)
+	s.Server.QueueCompletion("Hello there")
+
+	stream, err := s.Client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	// the loop of the ChatCompletionStream example
+	var content strings.Builder
+	var last chatgpt.ChatCompletionChunk
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		last = chunk
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "Hello there", content.String())
+	assert.Empty(t, last.Choices)
+	require.NotNil(t, last.Usage)
+	assert.Positive(t, last.Usage.TotalTokens)
+}
+
+func TestChatCompletionStream_Accumulate(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueCompletion("first answer", "second answer")
+
+	stream, err := client.Chat.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+		N:        chatgpt.Ptr(2),
+	})
+	require.NoError(t, err)
+	completion, err := stream.Accumulate()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, completion.ID)
+	assert.Equal(t, "chat.completion", completion.Object)
+	assert.Equal(t, "fp_openaitest", completion.SystemFingerprint)
+	require.Len(t, completion.Choices, 2)
+	assert.Equal(t, "assistant", completion.Choices[0].Role)
+	assert.Equal(t, "first answer", completion.Choices[0].Content)
+	assert.Equal(t, "second answer", completion.Choices[1].Content)
+	assert.Equal(t, chatgpt.FinishReasonStop, completion.Choices[1].FinishReason)
+	assert.Equal(t, 4, completion.Usage.CompletionTokens)
+}
+
+func TestChatCompletionStream_EventFormat(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.Inject(eventStream(": keep-alive comment\r\n\r\n" +
+		"data: {\"id\": \"chatcmpl_1\", \"choices\": [{\"index\": 0,\r\n" +
+		"data: \"delta\": {\"role\": \"assistant\", \"content\": \"Hel\"}}]}\r\n\r\n" +
+		"data: {\"id\": \"chatcmpl_1\", \"choices\": [{\"index\": 0, \"delta\": {\"content\": \"lo\"}, \"finish_reason\": \"length\"}]}\n\n" +
+		"data: [DONE]\n\n"))
+	client := s.Client
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.NoError(t, err)
+	completion, err := stream.Accumulate()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", completion.Content())
+	assert.Equal(t, chatgpt.FinishReasonLength, completion.Choices[0].FinishReason)
+	assert.Equal(t, "text/event-stream", server.Requests()[0].Header.Get("Accept"))
+}
+
+func TestChatCompletionStream_ToolCallFragments(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.Inject(eventStream(`data: {"choices": [{"index": 0, "delta": {"tool_calls": [{"index": 0, "id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": ""}}]}}]}` + "\n\n" +
+		`data: {"choices": [{"index": 0, "delta": {"tool_calls": [{"index": 0, "function": {"arguments": "{\"city\":"}}]}}]}` + "\n\n" +
+		"data: [DONE]\n\n"))
+	client := s.Client
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Weather?")},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var fragments []chatgpt.ToolCallDelta
+	for stream.Next() {
+		fragments = append(fragments, stream.Current().Choices[0].Delta.ToolCalls...)
+	}
+	require.NoError(t, stream.Err())
+	require.Len(t, fragments, 2)
+	assert.Equal(t, "call_1", fragments[0].ID)
+	assert.Equal(t, "get_weather", fragments[0].Function.Name)
+	assert.Equal(t, `{"city":`, fragments[1].Function.Arguments)
+}
+
+func TestChatCompletionStream_Errors(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.Inject(openaitest.Injection{StatusCode: http.StatusTooManyRequests, Code: "rate_limit_exceeded", Message: "Rate limit reached"})
+	_, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.True(t, openai.IsRateLimited(err))
+
+	server.Inject(eventStream(`data: {"choices": [{"index": 0, "delta": {"content": "Hel"}}]}` + "\n\n" +
+		`data: {"error": {"message": "The server had an error", "type": "server_error"}}` + "\n\n"))
+	stream, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.NoError(t, err)
+	completion, err := stream.Accumulate()
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "server_error", apiErr.Type)
+	assert.Equal(t, "Hel", completion.Content())
+}
+
+func TestChatCompletionAccumulator_Logprobs(t *testing.T) {
+	var accumulator chatgpt.ChatCompletionAccumulator
+	for _, token := range []string{"Hel", "lo"} {
+		accumulator.Add(chatgpt.ChatCompletionChunk{Choices: []chatgpt.ChunkChoice{{
+			Delta:    chatgpt.Delta{Content: token},
+			Logprobs: &chatgpt.Logprobs{Content: []chatgpt.TokenLogprob{{Token: token, Logprob: -0.5}}},
+		}}})
+	}
+
+	completion := accumulator.ChatCompletion()
+	assert.Equal(t, "Hello", completion.Content())
+	assert.Equal(t, []chatgpt.TokenLogprob{{Token: "Hel", Logprob: -0.5}, {Token: "lo", Logprob: -0.5}},
+		completion.Choices[0].Logprobs.Content)
+}
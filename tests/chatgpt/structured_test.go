@@ -0,0 +1,232 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type cityWeather struct {
+	City    string  `json:"city"`
+	Unit    string  `json:"unit" enum:"celsius,fahrenheit"`
+	Degrees float64 `json:"degrees"`
+}
+
+type chatPayload struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+	ResponseFormat map[string]any `json:"response_format"`
+}
+
+func requestPayload(t *testing.T, server *openaitest.Server, i int) chatPayload {
+	t.Helper()
+	var payload chatPayload
+	require.NoError(t, json.Unmarshal(server.Requests()[i].Body, &payload))
+	return payload
+}
+
+func TestCreateStructured_Happy(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"city":"Paris","unit":"celsius","degrees":21.5}`)
+
+	weather, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris?")},
+		chatgpt.WithRequest(chatgpt.ChatCompletionRequest{Model: "gpt-4o"}))
+	require.NoError(t, err)
+	assert.Equal(t, cityWeather{City: "Paris", Unit: "celsius", Degrees: 21.5}, weather)
+
+	payload := requestPayload(t, server, 0)
+	format, err := json.Marshal(payload.ResponseFormat)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "json_schema", "json_schema": {
+		"name": "cityWeather",
+		"strict": true,
+		"schema": {
+			"type": "object",
+			"properties": {
+				"city": {"type": "string"},
+				"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+				"degrees": {"type": "number"}
+			},
+			"required": ["city", "unit", "degrees"],
+			"additionalProperties": false
+		}
+	}}`, string(format))
+	assert.Equal(t, "gpt-4o", payload.Model)
+}
+
+func TestCreateStructured_Slice(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"value":[{"city":"Paris","unit":"celsius","degrees":21},{"city":"Rome","unit":"celsius","degrees":25}]}`)
+
+	forecast, err := chatgpt.CreateStructured[[]cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris and Rome?")})
+	require.NoError(t, err)
+	assert.Equal(t, []cityWeather{
+		{City: "Paris", Unit: "celsius", Degrees: 21},
+		{City: "Rome", Unit: "celsius", Degrees: 25},
+	}, forecast)
+
+	format, err := json.Marshal(requestPayload(t, server, 0).ResponseFormat)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "json_schema", "json_schema": {
+		"name": "response",
+		"strict": true,
+		"schema": {
+			"type": "object",
+			"properties": {
+				"value": {"type": "array", "items": {
+					"type": "object",
+					"properties": {
+						"city": {"type": "string"},
+						"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+						"degrees": {"type": "number"}
+					},
+					"required": ["city", "unit", "degrees"],
+					"additionalProperties": false
+				}}
+			},
+			"required": ["value"],
+			"additionalProperties": false
+		}
+	}}`, string(format))
+}
+
+func TestCreateStructured_Pointer(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"value":null}`)
+
+	weather, err := chatgpt.CreateStructured[*cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather on Mars?")})
+	require.NoError(t, err)
+	assert.Nil(t, weather)
+
+	payload := requestPayload(t, server, 0)
+	schema := payload.ResponseFormat["json_schema"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []any{"value"}, schema["required"])
+}
+
+func TestCreateStructured_Refusal(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueRefusal("I can't help with that")
+
+	_, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris?")})
+	var refusal *chatgpt.RefusalError
+	require.True(t, errors.As(err, &refusal))
+	assert.Equal(t, "I can't help with that", refusal.Refusal)
+}
+
+func TestCreateStructured_DecodeError(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"city":"Paris","wind":3}`)
+
+	_, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris?")})
+	var decodeErr *chatgpt.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, `{"city":"Paris","wind":3}`, decodeErr.Content)
+	assert.ErrorContains(t, err, `unknown field "wind"`)
+	assert.Len(t, server.Requests(), 1)
+}
+
+func TestCreateStructured_Reprompt(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"city":"Paris","degrees":"warm"}`, `{"city":"Paris","unit":"celsius","degrees":25}`)
+
+	weather, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris?")}, chatgpt.WithReprompt())
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, weather.Degrees)
+
+	payload := requestPayload(t, server, 1)
+	require.Len(t, payload.Messages, 3)
+	assert.Equal(t, "assistant", payload.Messages[1].Role)
+	assert.Equal(t, `{"city":"Paris","degrees":"warm"}`, payload.Messages[1].Content)
+	assert.Equal(t, "user", payload.Messages[2].Role)
+	assert.Contains(t, payload.Messages[2].Content, "cannot unmarshal string")
+}
+
+func TestCreateStructured_RepromptOnce(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion("not json", "still not json")
+
+	_, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Paris?")}, chatgpt.WithReprompt())
+	var decodeErr *chatgpt.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "still not json", decodeErr.Content)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestCreateStructured_JSONMode(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueCompletion(`{"city":"Rome","unit":"fahrenheit","degrees":80}`)
+
+	weather, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Rome?")}, chatgpt.WithJSONMode())
+	require.NoError(t, err)
+	assert.Equal(t, "Rome", weather.City)
+
+	payload := requestPayload(t, server, 0)
+	assert.Equal(t, map[string]any{"type": "json_object"}, payload.ResponseFormat)
+	require.Len(t, payload.Messages, 2)
+	assert.Equal(t, "system", payload.Messages[1].Role)
+	assert.Contains(t, payload.Messages[1].Content, `"required":["city","unit","degrees"]`)
+}
+
+func TestCreateStructured_JSONModeUnknownFields(t *testing.T) {
+	s := suite.NewFake(t)
+	s.Server.QueueCompletion(`{"city":"Rome","unit":"celsius","degrees":27,"humidity":40}`)
+
+	weather, err := chatgpt.CreateStructured[cityWeather](context.Background(), s.Client,
+		[]message.Message{message.NewUserMessage("Weather in Rome?")}, chatgpt.WithJSONMode())
+	require.NoError(t, err)
+	assert.Equal(t, cityWeather{City: "Rome", Unit: "celsius", Degrees: 27}, weather)
+}
+
+func TestChatCompletionStream_Refusal(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	server.QueueRefusal("No")
+
+	stream, err := s.Client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Hi")},
+	})
+	require.NoError(t, err)
+	completion, err := stream.Accumulate()
+	require.NoError(t, err)
+	assert.Equal(t, "No", completion.Choices[0].Message.Refusal)
+}
+
+func TestCreateStructured_ChatCompleter(t *testing.T) {
+	s := suite.NewFake(t)
+	s.Server.QueueCompletion(`{"city":"Oslo","unit":"celsius","degrees":-3}`)
+
+	client := completerFunc(func(ctx context.Context, request chatgpt.ChatCompletionRequest) (chatgpt.ChatCompletion, error) {
+		return s.Client.CreateChatCompletion(ctx, request)
+	})
+	weather, err := chatgpt.CreateStructured[cityWeather](context.Background(), client,
+		[]message.Message{message.NewUserMessage("Weather in Oslo?")})
+	require.NoError(t, err)
+	assert.Equal(t, cityWeather{City: "Oslo", Unit: "celsius", Degrees: -3}, weather)
+}
@@ -0,0 +1,121 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/ilborsch/openai-go/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var weatherTool = chatgpt.NewFunctionTool("get_weather", "Returns the current weather in a city", map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"city": map[string]any{"type": "string"},
+	},
+	"required": []string{"city"},
+})
+
+func TestToolCalling_RoundTrip(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueToolCalls(
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Rome"}`},
+	)
+	server.QueueCompletion("Sunny in both cities")
+
+	history := []message.Message{message.NewUserMessage("Weather in Paris and Rome?")}
+	completion, err := client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages:          history,
+		Tools:             []chatgpt.Tool{weatherTool},
+		ToolChoice:        chatgpt.ToolChoiceMode(chatgpt.ToolChoiceAuto),
+		ParallelToolCalls: chatgpt.Ptr(true),
+	})
+	require.NoError(t, err)
+	choice := completion.Choices[0]
+	assert.Equal(t, chatgpt.FinishReasonToolCalls, choice.FinishReason)
+	require.Len(t, choice.ToolCalls, 2)
+	assert.Equal(t, "function", choice.ToolCalls[0].Type)
+	assert.Equal(t, "get_weather", choice.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Rome"}`, choice.ToolCalls[1].Function.Arguments)
+
+	history = append(history, choice.ToMessage())
+	for _, call := range choice.ToolCalls {
+		history = append(history, message.NewToolMessage(call.ID, "sunny"))
+	}
+	completion, err = client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: history,
+		Tools:    []chatgpt.Tool{weatherTool},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in both cities", completion.Content())
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(server.Requests()[0].Body, &payload))
+	assert.Equal(t, "auto", payload["tool_choice"])
+	assert.Equal(t, true, payload["parallel_tool_calls"])
+	tools := payload["tools"].([]any)
+	assert.Equal(t, "get_weather", tools[0].(map[string]any)["function"].(map[string]any)["name"])
+
+	require.NoError(t, json.Unmarshal(server.Requests()[1].Body, &payload))
+	messages := payload["messages"].([]any)
+	require.Len(t, messages, 4)
+	assert.Equal(t, choice.ToolCalls[0].ID, messages[1].(map[string]any)["tool_calls"].([]any)[0].(map[string]any)["id"])
+	assert.Equal(t, map[string]any{"role": "tool", "content": "sunny", "tool_call_id": choice.ToolCalls[1].ID}, messages[3])
+}
+
+func TestToolCalling_UnknownToolCallID(t *testing.T) {
+	s := suite.NewFake(t)
+	client := s.Client
+
+	_, err := client.CreateChatCompletion(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{
+			message.NewUserMessage("Weather?"),
+			message.NewToolMessage("call_missing", "sunny"),
+		},
+	})
+	require.Error(t, err)
+}
+
+func TestToolChoice_JSON(t *testing.T) {
+	data, err := json.Marshal(chatgpt.ToolChoiceFunction("get_weather"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "function", "function": {"name": "get_weather"}}`, string(data))
+
+	data, err = json.Marshal(chatgpt.ToolChoiceMode(chatgpt.ToolChoiceRequired))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"required"`, string(data))
+}
+
+func TestToolCalling_StreamAccumulate(t *testing.T) {
+	s := suite.NewFake(t)
+	server := s.Server
+	client := s.Client
+	server.QueueToolCalls(
+		openaitest.ToolCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		openaitest.ToolCall{Name: "get_time", Arguments: `{}`},
+	)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), chatgpt.ChatCompletionRequest{
+		Messages: []message.Message{message.NewUserMessage("Weather and time?")},
+		Tools:    []chatgpt.Tool{weatherTool},
+	})
+	require.NoError(t, err)
+	completion, err := stream.Accumulate()
+	require.NoError(t, err)
+
+	choice := completion.Choices[0]
+	assert.Equal(t, chatgpt.FinishReasonToolCalls, choice.FinishReason)
+	require.Len(t, choice.ToolCalls, 2)
+	assert.NotEmpty(t, choice.ToolCalls[0].ID)
+	assert.Equal(t, "function", choice.ToolCalls[0].Type)
+	assert.Equal(t, "get_weather", choice.ToolCalls[0].Function.Name)
+	assert.Equal(t, `{"city":"Paris"}`, choice.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "get_time", choice.ToolCalls[1].Function.Name)
+}
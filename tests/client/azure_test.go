@@ -0,0 +1,61 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type azureRequest struct {
+	path       string
+	apiVersion string
+	apiKey     string
+	auth       string
+	body       map[string]any
+}
+
+func TestAzure_Routing(t *testing.T) {
+	var requests []azureRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := azureRequest{
+			path:       r.URL.Path,
+			apiVersion: r.URL.Query().Get("api-version"),
+			apiKey:     r.Header.Get("api-key"),
+			auth:       r.Header.Get("Authorization"),
+		}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &request.body)
+		requests = append(requests, request)
+		_, _ = w.Write([]byte(`{"id": "obj_123", "choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi!"}}]}`))
+	}))
+	defer server.Close()
+
+	deployments := map[string]string{chatgpt.DefaultModel: "my-gpt-35"}
+	client := openai.NewAzure(server.URL+"/", "azure-key", "2024-05-01-preview", deployments)
+
+	response, err := client.CreateCompletion([]message.Message{message.NewUserMessage("Hello!")})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi!", response)
+	_, err = client.CreateAssistant("name", "instructions", "vs_123", nil)
+	require.NoError(t, err)
+	_, err = client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/openai/deployments/my-gpt-35/chat/completions", requests[0].path)
+	assert.Equal(t, "/openai/assistants", requests[1].path)
+	assert.Equal(t, "my-gpt-35", requests[1].body["model"])
+	assert.Equal(t, "/openai/threads/thread_123/runs", requests[2].path)
+	for _, request := range requests {
+		assert.Equal(t, "2024-05-01-preview", request.apiVersion)
+		assert.Equal(t, "azure-key", request.apiKey)
+		assert.Empty(t, request.auth)
+	}
+}
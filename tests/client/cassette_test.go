@@ -0,0 +1,128 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCassette_RecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+	chatStory := []message.Message{message.NewUserMessage("Hello")}
+
+	server := openaitest.NewServer()
+	recorder, err := openaitest.NewRecorder(path, openaitest.ModeRecord)
+	require.NoError(t, err)
+	recorder.Transport = server.Client().Transport
+	client := openai.New("sk-secret", openai.WithBaseURL(server.URL+"/v1"), openai.WithHTTPClient(recorder.Client()))
+
+	recorded, err := client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+	fileID, err := client.UploadFile("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sk-secret")
+	assert.Len(t, recorder.Interactions(), 2)
+
+	recorder, err = openaitest.NewRecorder(path, openaitest.ModeReplay)
+	require.NoError(t, err)
+	client = openai.New("sk-other", openai.WithBaseURL(server.URL+"/v1"), openai.WithHTTPClient(recorder.Client()))
+
+	replayed, err := client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+	// multipart boundaries differ between the recorded and the replayed request
+	replayedFileID, err := client.UploadFile("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	assert.Equal(t, fileID, replayedFileID)
+
+	_, err = client.CreateCompletion([]message.Message{message.NewUserMessage("Something else")})
+	require.Error(t, err)
+}
+
+func TestCassette_BinaryUpload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upload.json")
+	// the binary comment of PDF files isn't valid UTF-8
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n")
+
+	server := openaitest.NewServer()
+	recorder, err := openaitest.NewRecorder(path, openaitest.ModeRecord)
+	require.NoError(t, err)
+	recorder.Transport = server.Client().Transport
+	client := openai.New("sk-secret", openai.WithBaseURL(server.URL), openai.WithHTTPClient(recorder.Client()))
+	fileID, err := client.UploadFile("report.pdf", pdf)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	server.Close()
+	assert.Equal(t, openaitest.BodyBase64, recorder.Interactions()[0].Request.BodyEncoding)
+
+	recorder, err = openaitest.NewRecorder(path, openaitest.ModeReplay)
+	require.NoError(t, err)
+	client = openai.New("sk-secret", openai.WithBaseURL(server.URL), openai.WithHTTPClient(recorder.Client()))
+	replayedFileID, err := client.UploadFile("report.pdf", pdf)
+	require.NoError(t, err)
+	assert.Equal(t, fileID, replayedFileID)
+}
+
+func TestCassette_ReplayRecordedError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.json")
+	server := openaitest.NewServer()
+	recorder, err := openaitest.NewRecorder(path, openaitest.ModeRecord)
+	require.NoError(t, err)
+	recorder.Transport = server.Client().Transport
+	client := openai.New("sk-secret", openai.WithBaseURL(server.URL+"/v1"), openai.WithHTTPClient(recorder.Client()))
+	_, err = client.GetAssistant("asst_missing")
+	require.True(t, openai.IsNotFound(err))
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	recorder, err = openaitest.NewRecorder(path, openaitest.ModeReplay)
+	require.NoError(t, err)
+	client = openai.New("sk-secret", openai.WithBaseURL(server.URL+"/v1"), openai.WithHTTPClient(recorder.Client()))
+	_, err = client.GetAssistant("asst_missing")
+	require.True(t, openai.IsNotFound(err))
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.NotEmpty(t, apiErr.RequestID)
+}
+
+func TestCassette_Missing(t *testing.T) {
+	_, err := openaitest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), openaitest.ModeReplay)
+	require.ErrorIs(t, err, openaitest.ErrNoCassette)
+}
+
+func TestCassette_ScrubsAccountHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "threads.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Openai-Organization", "org-secret")
+		w.Header().Set("Set-Cookie", "__cf_bm=session-secret; path=/")
+		w.Header().Set("X-Request-Id", "req_123")
+		_, _ = w.Write([]byte(`{"id": "thread_123"}`))
+	}))
+	defer server.Close()
+	recorder, err := openaitest.NewRecorder(path, openaitest.ModeRecord)
+	require.NoError(t, err)
+	client := openai.New("sk-secret", openai.WithBaseURL(server.URL), openai.WithOrganization("org-secret"),
+		openai.WithHTTPClient(recorder.Client()))
+
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "org-secret")
+	assert.NotContains(t, string(data), "session-secret")
+	assert.Contains(t, string(data), "req_123")
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateCompletionContext_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	chatStory := []message.Message{message.NewUserMessage("Hello!")}
+	response, err := client.CreateCompletionContext(ctx, chatStory)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, response)
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sentKeys returns the API keys of the requests received by `server`
+func sentKeys(server *openaitest.Server) []string {
+	var keys []string
+	for _, request := range server.Requests() {
+		keys = append(keys, strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer "))
+	}
+	return keys
+}
+
+func TestKeyPool_RoundRobin(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient(openai.WithCredentials(openai.NewKeyPool("key-a", "key-b")))
+
+	for i := 0; i < 4; i++ {
+		_, err := client.CreateThread()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"key-a", "key-b", "key-a", "key-b"}, sentKeys(server))
+}
+
+func TestKeyPool_Failover(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	// the first key is rate limited and the second one revoked
+	server.Inject(openaitest.Injection{StatusCode: http.StatusTooManyRequests})
+	server.Inject(openaitest.Injection{StatusCode: http.StatusUnauthorized})
+	client := server.NewClient(openai.WithCredentials(openai.NewKeyPool("key-a", "key-b", "key-c")))
+
+	threadID, err := client.CreateThread()
+	require.NoError(t, err)
+	assert.NotEmpty(t, threadID)
+	assert.Equal(t, []string{"key-a", "key-b", "key-c"}, sentKeys(server))
+
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	assert.Equal(t, "key-c", sentKeys(server)[3])
+}
+
+func TestKeyPool_AllKeysFailed(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	server.Inject(openaitest.Injection{StatusCode: http.StatusTooManyRequests, Times: 2})
+	client := server.NewClient(openai.WithCredentials(openai.NewKeyPool("key-a", "key-b")))
+
+	_, err := client.CreateThread()
+	assert.True(t, openai.IsRateLimited(err))
+	assert.Len(t, sentKeys(server), 2)
+}
+
+func TestFileKey_Rotation(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "api-key")
+	require.NoError(t, os.WriteFile(path, []byte("key-old\n"), 0o600))
+	client := server.NewClient(openai.WithCredentials(openai.FileKey(path)))
+
+	_, err := client.CreateThread()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("key-new\n"), 0o600))
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, future, future))
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-old", "key-new"}, sentKeys(server))
+}
+
+func TestEnvKey_Missing(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	t.Setenv("OPENAI_TEST_ROTATED_KEY", "")
+	client := server.NewClient(openai.WithCredentials(openai.EnvKey("OPENAI_TEST_ROTATED_KEY")))
+
+	_, err := client.CreateThread()
+	require.Error(t, err)
+	assert.Empty(t, sentKeys(server))
+
+	t.Setenv("OPENAI_TEST_ROTATED_KEY", "key-env")
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-env"}, sentKeys(server))
+}
+
+func TestCommandKey_Happy(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient(openai.WithCredentials(openai.CommandKey(time.Minute, "echo", "key-cmd")))
+
+	_, err := client.CreateThread()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-cmd"}, sentKeys(server))
+}
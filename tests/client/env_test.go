@@ -0,0 +1,101 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func clearOpenAIEnv(t *testing.T) {
+	for _, key := range []string{
+		openai.EnvAPIKey, openai.EnvBaseURL, openai.EnvOrgID, openai.EnvProjectID,
+		openai.EnvTimeout, openai.EnvProfile, openai.EnvConfigFile,
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewFromEnv_Happy(t *testing.T) {
+	clearOpenAIEnv(t)
+	server := openaitest.NewServer()
+	defer server.Close()
+	t.Setenv(openai.EnvAPIKey, "sk-env")
+	t.Setenv(openai.EnvBaseURL, server.URL)
+	t.Setenv(openai.EnvOrgID, "org_env")
+	t.Setenv(openai.EnvProjectID, "proj_env")
+	t.Setenv(openai.EnvTimeout, "30")
+
+	client, err := openai.NewFromEnv(openai.WithProject("proj_option"))
+	require.NoError(t, err)
+	threadID, err := client.CreateThread()
+	require.NoError(t, err)
+	assert.NotEmpty(t, threadID)
+	headers := server.Requests()[0].Header
+	assert.Equal(t, "Bearer sk-env", headers.Get("Authorization"))
+	assert.Equal(t, "org_env", headers.Get("OpenAI-Organization"))
+	assert.Equal(t, "proj_option", headers.Get("OpenAI-Project"))
+}
+
+func TestNewFromEnv_Profile(t *testing.T) {
+	clearOpenAIEnv(t)
+	server := openaitest.NewServer()
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{"profiles": {
+		"staging": {"api_key": "sk-staging", "base_url": "` + server.URL + `", "project": "proj_staging", "timeout": "10s"},
+		"prod": {"api_key": "sk-prod"}
+	}}`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	t.Setenv(openai.EnvConfigFile, path)
+	t.Setenv(openai.EnvProfile, "staging")
+	t.Setenv(openai.EnvOrgID, "org_env")
+
+	client, err := openai.NewFromEnv()
+	require.NoError(t, err)
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	headers := server.Requests()[0].Header
+	assert.Equal(t, "Bearer sk-staging", headers.Get("Authorization"))
+	assert.Equal(t, "proj_staging", headers.Get("OpenAI-Project"))
+	assert.Equal(t, "org_env", headers.Get("OpenAI-Organization"))
+
+	t.Setenv(openai.EnvProfile, "missing")
+	_, err = openai.NewFromEnv()
+	require.ErrorContains(t, err, `profile "missing" not found`)
+}
+
+func TestNewFromEnv_ValidationErrors(t *testing.T) {
+	clearOpenAIEnv(t)
+	_, err := openai.NewFromEnv()
+	require.ErrorIs(t, err, openai.ErrMissingAPIKey)
+
+	t.Setenv(openai.EnvAPIKey, "sk-env")
+	t.Setenv(openai.EnvTimeout, "soon")
+	_, err = openai.NewFromEnv()
+	require.ErrorContains(t, err, openai.EnvTimeout)
+
+	t.Setenv(openai.EnvTimeout, "")
+	t.Setenv(openai.EnvBaseURL, "localhost:8080")
+	_, err = openai.NewFromEnv()
+	require.ErrorContains(t, err, "invalid base URL")
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	_, err := openai.NewClient()
+	require.ErrorIs(t, err, openai.ErrMissingAPIKey)
+
+	_, err = openai.NewClient(openai.WithAPIKey("sk-test"), openai.WithTimeout(-1))
+	require.Error(t, err)
+
+	client, err := openai.NewClient(openai.WithCredentials(openai.StaticKey("sk-test")))
+	require.NoError(t, err)
+	require.NotNil(t, client)
+
+	require.PanicsWithError(t, openai.ErrMissingAPIKey.Error(), func() {
+		openai.New("")
+	})
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// failingClient returns a client of a fake server answering every request with `statusCode` and `body`
+func failingClient(t *testing.T, statusCode int, body string) *openai.OpenAI {
+	t.Helper()
+	server := openaitest.NewServer()
+	t.Cleanup(server.Close)
+	server.Inject(openaitest.Injection{StatusCode: statusCode, Body: body, Times: 100})
+	return server.NewClient()
+}
+
+func TestAPIError_RateLimited(t *testing.T) {
+	client := failingClient(t, http.StatusTooManyRequests,
+		`{"error": {"message": "Rate limit reached", "type": "requests", "param": null, "code": "rate_limit_exceeded"}}`)
+
+	_, err := client.CreateRun("thread_123", "asst_123")
+	require.Error(t, err)
+
+	var apiErr *openai.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, "requests", apiErr.Type)
+	assert.Equal(t, "rate_limit_exceeded", apiErr.Code)
+	assert.Empty(t, apiErr.Param)
+	assert.Equal(t, "Rate limit reached", apiErr.Message)
+	assert.Equal(t, "req_1", apiErr.RequestID)
+	assert.True(t, openai.IsRateLimited(err))
+	assert.False(t, openai.IsNotFound(err))
+}
+
+func TestAPIError_NotFound(t *testing.T) {
+	client := failingClient(t, http.StatusNotFound,
+		`{"error": {"message": "No assistant found", "type": "invalid_request_error", "param": null, "code": null}}`)
+
+	err := client.DeleteAssistant("asst_123")
+	assert.True(t, openai.IsNotFound(err))
+	assert.False(t, openai.IsAuth(err))
+}
+
+func TestAPIError_ContextLengthExceeded(t *testing.T) {
+	client := failingClient(t, http.StatusBadRequest,
+		`{"error": {"message": "Too long", "type": "invalid_request_error", "param": "messages", "code": "context_length_exceeded"}}`)
+
+	_, err := client.CreateCompletion([]message.Message{message.NewUserMessage("Hello!")})
+	assert.True(t, openai.IsContextLengthExceeded(err))
+
+	var apiErr *openai.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "messages", apiErr.Param)
+}
+
+func TestAPIError_NonJSONBody(t *testing.T) {
+	client := failingClient(t, http.StatusUnauthorized, "invalid api key")
+
+	_, err := client.CreateThread()
+	assert.True(t, openai.IsAuth(err))
+	assert.Contains(t, err.Error(), "invalid api key")
+	assert.Contains(t, err.Error(), "req_1")
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_RetryReusesKey(t *testing.T) {
+	var calls atomic.Int32
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "run_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key",
+		openai.WithBaseURL(server.URL),
+		openai.WithRetryPolicy(openai.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+	runID, err := client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+	assert.Equal(t, "run_123", runID)
+
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+
+	_, err = client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+	assert.NotEqual(t, keys[0], keys[2])
+}
+
+func TestIdempotency_CallerSuppliedKey(t *testing.T) {
+	keys := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.Method+" "+r.URL.Path] = r.Header.Get("Idempotency-Key")
+		_, _ = w.Write([]byte(`{"id": "file_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestIdempotencyKey("upload-report-42"))
+	_, err := client.UploadFileContext(ctx, "report.txt", []byte("hello"))
+	require.NoError(t, err)
+	_, err = client.GetVectorStoreFiles("vs_123")
+	require.NoError(t, err)
+
+	assert.Equal(t, "upload-report-42", keys["POST /files"])
+	assert.Empty(t, keys["GET /vector_stores/vs_123/files"])
+}
+
+func TestIdempotency_CallerSuppliedKeyBoundToOneOperation(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Method+" "+r.URL.Path+" "+r.Header.Get("Idempotency-Key"))
+		_, _ = w.Write([]byte(`{"id": "thread_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestIdempotencyKey("create-thread-7"))
+	_, err := client.CreateThreadContext(ctx)
+	require.NoError(t, err)
+	_, err = client.CreateThreadContext(ctx)
+	require.NoError(t, err)
+	_, err = client.CreateRunContext(ctx, "thread_123", "asst_123")
+	require.ErrorIs(t, err, openai.ErrIdempotencyKeyReused)
+
+	assert.Equal(t, []string{
+		"POST /threads create-thread-7",
+		"POST /threads create-thread-7",
+	}, keys)
+}
+
+func TestIdempotency_CallerSuppliedKeyBoundToOnePayload(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+	threadID, err := client.CreateThread()
+	require.NoError(t, err)
+
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestIdempotencyKey("add-message-3"))
+	require.NoError(t, client.AddMessageToThreadContext(ctx, threadID, "first"))
+	require.NoError(t, client.AddMessageToThreadContext(ctx, threadID, "first"))
+	err = client.AddMessageToThreadContext(ctx, threadID, "second")
+	require.ErrorIs(t, err, openai.ErrIdempotencyKeyReused)
+
+	// multipart boundaries differ between repeated uploads
+	ctx = openai.WithRequestOptions(context.Background(), openai.RequestIdempotencyKey("upload-notes-3"))
+	_, err = client.UploadFileContext(ctx, "notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	_, err = client.UploadFileContext(ctx, "notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	_, err = client.UploadFileContext(ctx, "notes.txt", []byte("other notes"))
+	require.ErrorIs(t, err, openai.ErrIdempotencyKeyReused)
+
+	requests := server.Requests()
+	require.Len(t, requests, 5)
+	for _, request := range requests[1:3] {
+		assert.Equal(t, "add-message-3", request.Header.Get("Idempotency-Key"))
+	}
+	for _, request := range requests[3:] {
+		assert.Equal(t, "upload-notes-3", request.Header.Get("Idempotency-Key"))
+	}
+}
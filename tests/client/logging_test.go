@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bytes"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const secretKey = "sk-secret-test-key"
+
+func TestLogging_RequestLifecycle(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "run_123"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := openai.New(secretKey,
+		openai.WithBaseURL(server.URL),
+		openai.WithLogger(logger),
+		openai.WithRetryPolicy(openai.RetryPolicy{MaxAttempts: 2}),
+	)
+	_, err := client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+
+	output := logs.String()
+	assert.Contains(t, output, `"msg":"openai request started"`)
+	assert.Contains(t, output, `"msg":"openai request retrying"`)
+	assert.Contains(t, output, `"msg":"openai request finished"`)
+	assert.Contains(t, output, `"level":"WARN"`)
+	assert.Contains(t, output, `"endpoint":"threads.runs.create"`)
+	assert.Contains(t, output, `"request_id":"req_123"`)
+	assert.Contains(t, output, `"status":200`)
+	assert.Contains(t, output, `"attempt":2`)
+	assert.NotContains(t, output, "request_body")
+	assert.NotContains(t, output, secretKey)
+}
+
+func TestLogging_BodiesRedactedAndTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error": "` + secretKey + `"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	client := openai.New(secretKey,
+		openai.WithBaseURL(server.URL),
+		openai.WithLogger(logger),
+		openai.WithLogLevel(slog.LevelInfo),
+		openai.WithBodyLogging(40),
+		openai.WithTimeout(time.Second),
+	)
+	err := client.AddMessageToThread("thread_123", "my key is "+secretKey+" and a lot more text after it")
+	require.NoError(t, err)
+
+	output := logs.String()
+	assert.Contains(t, output, "request_body")
+	assert.Contains(t, output, "response_body")
+	assert.Contains(t, output, "[REDACTED]")
+	assert.Contains(t, output, "(truncated)")
+	assert.NotContains(t, output, secretKey)
+}
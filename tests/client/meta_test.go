@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseMeta_Happy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_123")
+		w.Header().Set("openai-processing-ms", "250")
+		w.Header().Set("openai-model", "gpt-4o-2024-08-06")
+		w.Header().Set("openai-organization", "org_123")
+		w.Header().Set("x-ratelimit-limit-requests", "500")
+		w.Header().Set("x-ratelimit-remaining-requests", "499")
+		w.Header().Set("x-ratelimit-reset-requests", "120ms")
+		w.Header().Set("x-ratelimit-limit-tokens", "30000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "29000")
+		w.Header().Set("x-ratelimit-reset-tokens", "2s")
+		_, _ = w.Write([]byte(`{"id": "asst_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	var meta openai.ResponseMeta
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestMeta(&meta))
+	assistantID, err := client.CreateAssistantContext(ctx, "name", "instructions", "vs_123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "asst_123", assistantID)
+
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, "req_123", meta.RequestID)
+	assert.Equal(t, 250*time.Millisecond, meta.ProcessingTime)
+	assert.Equal(t, "gpt-4o-2024-08-06", meta.Model)
+	assert.Equal(t, "org_123", meta.Organization)
+	assert.Equal(t, openai.RateLimitInfo{
+		LimitRequests:     500,
+		LimitTokens:       30000,
+		RemainingRequests: 499,
+		RemainingTokens:   29000,
+		ResetRequests:     120 * time.Millisecond,
+		ResetTokens:       2 * time.Second,
+	}, meta.RateLimit)
+	assert.Equal(t, "req_123", meta.Header.Get("x-request-id"))
+}
+
+func TestResponseMeta_FailedCall(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	server.Inject(openaitest.Injection{StatusCode: http.StatusNotFound, Body: `{"error": {"message": "No run found"}}`})
+	client := server.NewClient()
+
+	var meta openai.ResponseMeta
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestMeta(&meta))
+	_, err := client.GetRunContext(ctx, "thread_123", "run_123")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, meta.StatusCode)
+	assert.Equal(t, "req_1", meta.RequestID)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type observedCall struct {
+	endpoint    string
+	method      string
+	payloadSize int64
+	latency     time.Duration
+	status      int
+}
+
+func TestMiddleware_ObservesCalls(t *testing.T) {
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant")
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "run_123"}`))
+	}))
+	defer server.Close()
+
+	var calls []observedCall
+	var order []string
+	metrics := func(next openai.Handler) openai.Handler {
+		return func(call *openai.Call) (*http.Response, error) {
+			order = append(order, "metrics")
+			start := time.Now()
+			resp, err := next(call)
+			observed := observedCall{
+				endpoint:    call.Endpoint,
+				method:      call.Request.Method,
+				payloadSize: call.Request.ContentLength,
+				latency:     time.Since(start),
+			}
+			if resp != nil {
+				observed.status = resp.StatusCode
+			}
+			calls = append(calls, observed)
+			return resp, err
+		}
+	}
+	tenantHeader := func(next openai.Handler) openai.Handler {
+		return func(call *openai.Call) (*http.Response, error) {
+			order = append(order, "tenant")
+			call.Request.Header.Set("X-Tenant", "acme")
+			return next(call)
+		}
+	}
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithMiddleware(metrics, tenantHeader))
+	_, err := client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+	_, err = client.GetRun("thread_123", "run_123")
+	require.Error(t, err)
+
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, []string{"metrics", "tenant", "metrics", "tenant"}, order)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "threads.runs.create", calls[0].endpoint)
+	assert.Equal(t, http.MethodPost, calls[0].method)
+	assert.Equal(t, int64(len(`{"assistant_id":"asst_123"}`)), calls[0].payloadSize)
+	assert.Equal(t, http.StatusOK, calls[0].status)
+	assert.Positive(t, calls[0].latency)
+	assert.Equal(t, "threads.runs.retrieve", calls[1].endpoint)
+	assert.Equal(t, http.StatusNotFound, calls[1].status)
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/assistants"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFakeServer_Completion(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	chatStory := []message.Message{message.NewUserMessage("Hello")}
+	response, err := client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+	assert.Equal(t, "You said: Hello", response)
+
+	server.QueueCompletion("scripted")
+	response, err = client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+	assert.Equal(t, "scripted", response)
+
+	_, err = client.CreateCompletion(nil)
+	require.Error(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "/chat/completions", requests[0].Path)
+	assert.Equal(t, "Bearer sk-openaitest", requests[0].Header.Get("Authorization"))
+}
+
+func TestFakeServer_Files(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	fileID, err := client.UploadFile("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	content, ok := server.FileContent(fileID)
+	require.True(t, ok)
+	assert.Equal(t, "some notes", string(content))
+
+	_, err = client.UploadFile("archive.zip", []byte("zip"))
+	require.Error(t, err)
+	_, err = client.UploadFile("empty.txt", nil)
+	require.Error(t, err)
+
+	require.NoError(t, client.DeleteFile(fileID))
+	require.True(t, openai.IsNotFound(client.DeleteFile(fileID)))
+}
+
+func TestFakeServer_VectorStoreFiles(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	fileID, err := client.UploadFile("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	storeID, err := client.CreateVectorStore("")
+	require.NoError(t, err)
+
+	require.NoError(t, client.AddVectorStoreFile(storeID, fileID))
+	require.Error(t, client.AddVectorStoreFile(storeID, "file_missing"))
+	files, err := client.GetVectorStoreFiles(storeID)
+	require.NoError(t, err)
+	require.Len(t, files.Files, 1)
+	assert.Equal(t, fileID, files.Files[0].FileID)
+
+	require.NoError(t, client.DeleteVectorStoreFile(storeID, fileID))
+	require.Error(t, client.DeleteVectorStoreFile(storeID, fileID))
+	require.NoError(t, client.DeleteVectorStore(storeID))
+}
+
+func TestFakeServer_Inject(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	var events []openai.RetryEvent
+	client := server.NewClient(openai.WithRetryPolicy(fastRetryPolicy(&events)))
+	server.Inject(openaitest.Injection{
+		Method:     http.MethodPost,
+		Path:       "/assistants",
+		StatusCode: http.StatusTooManyRequests,
+		Code:       "rate_limit_exceeded",
+		Message:    "Rate limit reached",
+		Times:      2,
+	})
+
+	storeID, err := client.CreateVectorStore("store")
+	require.NoError(t, err)
+	assistantID, err := client.CreateAssistant("name", "instructions", storeID, []assistants.Tool{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, assistantID)
+	assert.Len(t, server.Requests(), 4)
+	assert.Len(t, events, 2)
+
+	server.Inject(openaitest.Injection{StatusCode: http.StatusTooManyRequests, Times: 3})
+	_, err = client.GetAssistant(assistantID)
+	require.True(t, openai.IsRateLimited(err))
+}
+
+func TestFakeServer_AssistantWithoutToolResources(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/v1/assistants", strings.NewReader(`{"model":"gpt-4o"}`))
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer test-key")
+	request.Header.Set("OpenAI-Beta", "assistants=v2")
+	response, err := server.Client().Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	var created map[string]any
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&created))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "assistant", created["object"])
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithBaseURL_Happy(t *testing.T) {
+	var gotPath, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Tenant")
+		_, _ = w.Write([]byte(`{"id": "thread_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key",
+		openai.WithBaseURL(server.URL+"/v1"),
+		openai.WithHTTPClient(server.Client()),
+		openai.WithDefaultHeaders(map[string]string{"X-Tenant": "acme"}),
+	)
+	threadID, err := client.CreateThread()
+	require.NoError(t, err)
+	assert.Equal(t, "thread_123", threadID)
+	assert.Equal(t, "/v1/threads", gotPath)
+	assert.Equal(t, "acme", gotHeader)
+}
+
+func TestWithTimeout_Expired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"id": "thread_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithTimeout(20*time.Millisecond))
+	threadID, err := client.CreateThread()
+	require.Error(t, err)
+	assert.Empty(t, threadID)
+}
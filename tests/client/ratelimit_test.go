@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/ilborsch/openai-go/openai/openaitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// exhaustedLimit is the Injection reporting that no requests are left until the limit resets in 150ms
+var exhaustedLimit = openaitest.Injection{
+	Header: http.Header{
+		"X-Ratelimit-Remaining-Requests": {"0"},
+		"X-Ratelimit-Reset-Requests":     {"150ms"},
+		"X-Ratelimit-Remaining-Tokens":   {"10000"},
+		"X-Ratelimit-Reset-Tokens":       {"1s"},
+	},
+	Body:  `{"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi!"}}]}`,
+	Times: 2,
+}
+
+func TestRateLimiter_WaitsForReset(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	server.Inject(exhaustedLimit)
+	client := server.NewClient(openai.WithRateLimiter(&openai.RateLimiter{}))
+	chatStory := []message.Message{message.NewUserMessage("Hello!")}
+
+	_, err := client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestRateLimiter_ContextCancelled(t *testing.T) {
+	server := openaitest.NewServer()
+	defer server.Close()
+	server.Inject(exhaustedLimit)
+	client := server.NewClient(openai.WithRateLimiter(&openai.RateLimiter{}))
+	chatStory := []message.Message{message.NewUserMessage("Hello!")}
+
+	_, err := client.CreateCompletion(chatStory)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateCompletionContext(ctx, chatStory)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, server.Requests(), 1)
+}
@@ -0,0 +1,19 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServices_SharedWithFlatMethods(t *testing.T) {
+	client := openai.New("test-key")
+	assert.Same(t, client.Chat, client.ChatGPT)
+	assert.Same(t, client.VectorStores, client.Assistants.VectorStores)
+	assert.Same(t, client.Threads, client.Assistants.Threads)
+	assert.Same(t, client.Messages, client.Assistants.Messages)
+	assert.Same(t, client.Runs, client.Assistants.Runs)
+
+	var flat openai.OpenAIClient = client
+	assert.NotNil(t, flat)
+}
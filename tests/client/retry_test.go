@@ -0,0 +1,108 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy(events *[]openai.RetryEvent) openai.RetryPolicy {
+	return openai.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		OnRetry: func(event openai.RetryEvent) {
+			*events = append(*events, event)
+		},
+	}
+}
+
+func TestRetry_RateLimitedThenSuccess(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "run_123"}`))
+	}))
+	defer server.Close()
+
+	var events []openai.RetryEvent
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithRetryPolicy(fastRetryPolicy(&events)))
+	runID, err := client.CreateRun("thread_123", "asst_123")
+	require.NoError(t, err)
+	assert.Equal(t, "run_123", runID)
+	assert.EqualValues(t, 2, calls.Load())
+
+	require.Len(t, events, 1)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, http.StatusTooManyRequests, events[0].StatusCode)
+	assert.Equal(t, time.Duration(0), events[0].Delay)
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestRetry_ServerErrorOnCompletion(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi!"}}]}`))
+	}))
+	defer server.Close()
+
+	var events []openai.RetryEvent
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithRetryPolicy(fastRetryPolicy(&events)))
+	response, err := client.CreateCompletion([]message.Message{message.NewUserMessage("Hello!")})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi!", response)
+	assert.Len(t, events, 2)
+}
+
+func TestRetry_NonIdempotentServerError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var events []openai.RetryEvent
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithRetryPolicy(fastRetryPolicy(&events)))
+	err := client.Modify("asst_123", "new instructions", "", 0)
+	require.Error(t, err)
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Empty(t, events)
+}
+
+func TestRetry_GiveUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "2ms")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var events []openai.RetryEvent
+	client := openai.New("test-key", openai.WithBaseURL(server.URL), openai.WithRetryPolicy(fastRetryPolicy(&events)))
+	_, err := client.GetRun("thread_123", "run_123")
+	assert.True(t, openai.IsRateLimited(err))
+	assert.EqualValues(t, 3, calls.Load())
+	require.Len(t, events, 2)
+	assert.Equal(t, 2*time.Millisecond, events[1].Delay)
+}
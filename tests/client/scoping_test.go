@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScoping_OrganizationAndProject(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		_, _ = w.Write([]byte(`{"id": "obj_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key",
+		openai.WithBaseURL(server.URL),
+		openai.WithOrganization("org_default"),
+		openai.WithProject("proj_default"),
+	)
+	_, err := client.UploadFile("test_file.txt", []byte("hello"))
+	require.NoError(t, err)
+
+	ctx := openai.WithRequestOptions(context.Background(), openai.RequestProject("proj_batch"))
+	ctx = openai.WithRequestOptions(ctx, openai.RequestHeader("X-Tenant", "acme"))
+	_, err = client.CreateVectorStoreContext(ctx, "store")
+	require.NoError(t, err)
+
+	require.Len(t, headers, 2)
+	assert.Equal(t, "org_default", headers[0].Get("OpenAI-Organization"))
+	assert.Equal(t, "proj_default", headers[0].Get("OpenAI-Project"))
+	assert.Equal(t, "org_default", headers[1].Get("OpenAI-Organization"))
+	assert.Equal(t, "proj_batch", headers[1].Get("OpenAI-Project"))
+	assert.Equal(t, "acme", headers[1].Get("X-Tenant"))
+}
+
+func TestScoping_NotConfigured(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"id": "thread_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	_, err := client.CreateThread()
+	require.NoError(t, err)
+	assert.NotContains(t, header, "Openai-Organization")
+	assert.NotContains(t, header, "Openai-Project")
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/chatgpt/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransport_CommonHeaders(t *testing.T) {
+	requests := make(map[string]http.Header)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path] = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"id": "obj_123", "data": []}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	_, err := client.CreateVectorStore("store")
+	require.NoError(t, err)
+	_, err = client.CreateThread()
+	require.NoError(t, err)
+	require.NoError(t, client.AddMessageToThread("thread_123", "Hello!"))
+	_, err = client.GetRun("thread_123", "run_123")
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteFile("file_123"))
+
+	require.Len(t, requests, 5)
+	for endpoint, header := range requests {
+		assert.Equal(t, "Bearer test-key", header.Get("Authorization"), endpoint)
+		if endpoint == "DELETE /files/file_123" {
+			assert.Empty(t, header.Get("OpenAI-Beta"), endpoint)
+		} else {
+			assert.Equal(t, "assistants=v2", header.Get("OpenAI-Beta"), endpoint)
+		}
+	}
+	assert.Equal(t, "application/json", requests["POST /vector_stores"].Get("Content-Type"))
+}
+
+func TestTransport_UploadFileMultipart(t *testing.T) {
+	var purpose, filename, content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		purpose = r.FormValue("purpose")
+		file, header, err := r.FormFile("file")
+		if err == nil {
+			data, _ := io.ReadAll(file)
+			filename, content = header.Filename, string(data)
+		}
+		_, _ = w.Write([]byte(`{"id": "file_123"}`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	fileID, err := client.UploadFile("../test-data/test_file.txt", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "file_123", fileID)
+	assert.Equal(t, "assistants", purpose)
+	assert.Equal(t, "test_file.txt", filename)
+	assert.Equal(t, "hello", content)
+}
+
+func TestTransport_MalformedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices": [`))
+	}))
+	defer server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	response, err := client.CreateCompletion([]message.Message{message.NewUserMessage("Hello!")})
+	require.Error(t, err)
+	assert.Empty(t, response)
+}
+
+func TestTransport_ConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := openai.New("test-key", openai.WithBaseURL(server.URL))
+	response, err := client.CreateCompletion([]message.Message{message.NewUserMessage("Hello!")})
+	require.Error(t, err)
+	assert.Empty(t, response)
+}
@@ -1,6 +1,7 @@
 package files
 
 import (
+	"github.com/ilborsch/openai-go/openai"
 	"github.com/ilborsch/openai-go/tests/suite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,7 +59,7 @@ func TestFileUpload_InvalidFormat(t *testing.T) {
 
 func TestDeleteFile_Happy(t *testing.T) {
 	s := suite.New(t)
-	filename := "./test-data/test_file.txt"
+	filename := "../test-data/test_file.txt"
 	file, err := os.Open(filename)
 	require.NoError(t, err)
 
@@ -79,3 +80,19 @@ func TestDeleteFile_NonExistingFile(t *testing.T) {
 	err := s.Client.Files.DeleteFile("random_id_123")
 	require.Error(t, err)
 }
+
+func TestFiles_ResourceMethods(t *testing.T) {
+	s := suite.NewFake(t)
+	fileID, err := s.Client.Files.Create("notes.txt", []byte("some notes"))
+	require.NoError(t, err)
+	file, err := s.Client.Files.Get(fileID)
+	require.NoError(t, err)
+	assert.Equal(t, "notes.txt", file.Filename)
+	files, err := s.Client.Files.List()
+	require.NoError(t, err)
+	require.Len(t, files.Files, 1)
+
+	require.NoError(t, s.Client.Files.Delete(fileID))
+	_, err = s.Client.Files.Get(fileID)
+	require.True(t, openai.IsNotFound(err))
+}
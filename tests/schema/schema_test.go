@@ -0,0 +1,240 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ilborsch/openai-go/openai/assistants"
+	"github.com/ilborsch/openai-go/openai/chatgpt"
+	"github.com/ilborsch/openai-go/openai/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type forecastDay struct {
+	Date    time.Time `json:"date"`
+	Celsius float64   `json:"celsius" description:"Temperature at noon"`
+}
+
+type forecast struct {
+	City     string        `json:"city" description:"City name in English"`
+	Unit     string        `json:"unit" enum:"celsius,fahrenheit"`
+	Days     []forecastDay `json:"days"`
+	Notes    *string       `json:"notes"`
+	Alerts   int           `json:"alerts,omitempty"`
+	Internal string        `json:"-"`
+	hidden   string
+}
+
+func marshalSchema(t *testing.T, s *schema.Schema) string {
+	t.Helper()
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSchema_Struct(t *testing.T) {
+	s, err := schema.For[forecast]()
+	require.NoError(t, err)
+	assert.True(t, s.Strict())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"city": {"type": "string", "description": "City name in English"},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+			"days": {"type": "array", "items": {
+				"type": "object",
+				"properties": {
+					"date": {"type": "string", "format": "date-time"},
+					"celsius": {"type": "number", "description": "Temperature at noon"}
+				},
+				"required": ["date", "celsius"],
+				"additionalProperties": false
+			}},
+			"notes": {"type": ["string", "null"]},
+			"alerts": {"type": ["integer", "null"]}
+		},
+		"required": ["city", "unit", "days", "notes", "alerts"],
+		"additionalProperties": false
+	}`, marshalSchema(t, s))
+}
+
+func TestSchema_NullableStructAndEmbedded(t *testing.T) {
+	type base struct {
+		ID int `json:"id"`
+	}
+	type item struct {
+		base
+		Parent *forecastDay `json:"parent" description:"Previous day"`
+		Levels []int        `json:"levels" enum:"1,2,3"`
+	}
+
+	s, err := schema.For[item]()
+	require.NoError(t, err)
+	assert.True(t, s.Strict())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"parent": {"description": "Previous day", "anyOf": [
+				{
+					"type": "object",
+					"properties": {
+						"date": {"type": "string", "format": "date-time"},
+						"celsius": {"type": "number", "description": "Temperature at noon"}
+					},
+					"required": ["date", "celsius"],
+					"additionalProperties": false
+				},
+				{"type": "null"}
+			]},
+			"levels": {"type": "array", "items": {"type": "integer", "enum": [1, 2, 3]}}
+		},
+		"required": ["id", "parent", "levels"],
+		"additionalProperties": false
+	}`, marshalSchema(t, s))
+}
+
+func TestSchema_NullableEnum(t *testing.T) {
+	type options struct {
+		Mode   *string  `json:"mode" enum:"x,y"`
+		Sizes  []string `json:"sizes,omitempty" enum:"s,m"`
+		Levels []*int   `json:"levels" enum:"1,2"`
+	}
+
+	s, err := schema.For[options]()
+	require.NoError(t, err)
+	assert.True(t, s.Strict())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"mode": {"type": ["string", "null"], "enum": ["x", "y", null]},
+			"sizes": {"anyOf": [
+				{"type": "array", "items": {"type": "string", "enum": ["s", "m"]}},
+				{"type": "null"}
+			]},
+			"levels": {"type": "array", "items": {"type": ["integer", "null"], "enum": [1, 2, null]}}
+		},
+		"required": ["mode", "sizes", "levels"],
+		"additionalProperties": false
+	}`, marshalSchema(t, s))
+}
+
+type treeNode struct {
+	Name     string     `json:"name"`
+	Children []treeNode `json:"children"`
+	Link     *linkNode  `json:"link"`
+}
+
+type linkNode struct {
+	Target string    `json:"target"`
+	Next   *linkNode `json:"next"`
+}
+
+func TestSchema_Recursive(t *testing.T) {
+	s, err := schema.For[treeNode]()
+	require.NoError(t, err)
+	assert.True(t, s.Strict())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#"}},
+			"link": {"anyOf": [{"$ref": "#/$defs/linkNode"}, {"type": "null"}]}
+		},
+		"required": ["name", "children", "link"],
+		"additionalProperties": false,
+		"$defs": {
+			"linkNode": {
+				"type": "object",
+				"properties": {
+					"target": {"type": "string"},
+					"next": {"anyOf": [{"$ref": "#/$defs/linkNode"}, {"type": "null"}]}
+				},
+				"required": ["target", "next"],
+				"additionalProperties": false
+			}
+		}
+	}`, marshalSchema(t, s))
+}
+
+func TestSchema_MapIsNotStrict(t *testing.T) {
+	type tags struct {
+		Values map[string]int `json:"values"`
+	}
+
+	s, err := schema.For[tags]()
+	require.NoError(t, err)
+	assert.False(t, s.Strict())
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {"values": {"type": "object", "additionalProperties": {"type": "integer"}}},
+		"required": ["values"],
+		"additionalProperties": false
+	}`, marshalSchema(t, s))
+}
+
+func TestSchema_Bytes(t *testing.T) {
+	type checksum struct {
+		Data []byte  `json:"data"`
+		Hash [4]byte `json:"hash"`
+	}
+
+	s, err := schema.For[checksum]()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"data": {"type": "string"},
+			"hash": {"type": "array", "items": {"type": "integer"}}
+		},
+		"required": ["data", "hash"],
+		"additionalProperties": false
+	}`, marshalSchema(t, s))
+
+	// the schema matches encoding/json: base64 for byte slices and numbers for byte arrays
+	data, err := json.Marshal(checksum{Data: []byte("hi"), Hash: [4]byte{1, 2, 3, 4}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": "aGk=", "hash": [1, 2, 3, 4]}`, string(data))
+}
+
+func TestSchema_Errors(t *testing.T) {
+	type withChannel struct {
+		Updates chan int `json:"updates"`
+	}
+	_, err := schema.For[withChannel]()
+	assert.True(t, errors.Is(err, schema.ErrUnsupportedType))
+
+	type badEnum struct {
+		Level int `json:"level" enum:"low,high"`
+	}
+	_, err = schema.Reflect(reflect.TypeOf(badEnum{}))
+	assert.ErrorContains(t, err, `invalid integer enum value "low"`)
+}
+
+func TestSchema_FunctionTools(t *testing.T) {
+	s, err := schema.For[struct {
+		City string `json:"city"`
+	}]()
+	require.NoError(t, err)
+
+	tool := chatgpt.NewFunctionTool("get_weather", "Returns the weather in a city", s)
+	assert.True(t, tool.Function.Strict)
+
+	assistantTool := assistants.NewFunctionTool("get_weather", "Returns the weather in a city", s)
+	data, err := json.Marshal(assistantTool)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "function", "function": {
+		"name": "get_weather",
+		"description": "Returns the weather in a city",
+		"parameters": {
+			"type": "object",
+			"properties": {"city": {"type": "string"}},
+			"required": ["city"],
+			"additionalProperties": false
+		},
+		"strict": true
+	}}`, string(data))
+}
@@ -1,37 +1,98 @@
 package suite
 
 import (
+	"cmp"
+	"errors"
 	"github.com/ilborsch/openai-go/openai"
+	"github.com/ilborsch/openai-go/openai/openaitest"
 	"github.com/joho/godotenv"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// cassettesDir keeps the interactions recorded with OPENAI_CASSETTE=record
+const cassettesDir = "../../tests/test-data/cassettes"
+
 type Suite struct {
 	*testing.T
 	Client *openai.OpenAI
+	// Server is the fake OpenAI API the Client talks to, nil when testing against the real API
+	Server *openaitest.Server
 }
 
+// New creates a test suite with a client of the real OpenAI API configured by openai.NewFromEnv
+// when OPENAI_API_KEY (or API_KEY) is set in tests/test-data/.env or the environment,
+// and of an openaitest.Server otherwise.
+// OPENAI_CASSETTE=record records the real API traffic of each test to tests/test-data/cassettes,
+// OPENAI_CASSETTE=replay replays it offline.
 func New(t *testing.T) *Suite {
 	t.Helper()
 	t.Parallel()
 
-	APIKey := mustLoadAPIKey()
-	client := openai.New(APIKey)
+	APIKey := loadAPIKey()
+	switch os.Getenv("OPENAI_CASSETTE") {
+	case "record":
+		if APIKey == "" {
+			t.Fatal("API_KEY is required to record cassettes")
+		}
+		return newCassetteSuite(t, APIKey, openaitest.ModeRecord)
+	case "replay":
+		return newCassetteSuite(t, "sk-replay", openaitest.ModeReplay)
+	}
+
+	client, err := openai.NewFromEnv(openai.WithAPIKey(APIKey))
+	if errors.Is(err, openai.ErrMissingAPIKey) {
+		return newFakeSuite(t)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
 	return &Suite{
 		T:      t,
 		Client: client,
 	}
 }
 
-func mustLoadAPIKey() string {
-	if err := godotenv.Load("../../tests/test-data/.env"); err != nil {
-		panic("error loading .env file: " + err.Error())
+// NewFake creates a test suite with a client of an openaitest.Server, for tests scripting
+// the replies of the API (f.e. with QueueCompletion) or asserting on the requests it received
+func NewFake(t *testing.T) *Suite {
+	t.Helper()
+	t.Parallel()
+	return newFakeSuite(t)
+}
+
+func newFakeSuite(t *testing.T) *Suite {
+	server := openaitest.NewServer()
+	t.Cleanup(server.Close)
+	return &Suite{
+		T:      t,
+		Client: server.NewClient(),
+		Server: server,
 	}
+}
 
-	apiKey := os.Getenv("API_KEY")
-	if apiKey == "" {
-		panic("API_KEY not found in .env file")
+func newCassetteSuite(t *testing.T, apiKey string, mode openaitest.Mode) *Suite {
+	path := filepath.Join(cassettesDir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+	recorder, err := openaitest.NewRecorder(path, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := recorder.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	return &Suite{
+		T:      t,
+		Client: openai.New(apiKey, openai.WithHTTPClient(recorder.Client())),
 	}
-	return apiKey
+}
+
+func loadAPIKey() string {
+	// the .env file is optional: without it tests run against the fake server
+	_ = godotenv.Load("../../tests/test-data/.env")
+	// API_KEY is the name used by .env files written before OPENAI_API_KEY was supported
+	return cmp.Or(os.Getenv(openai.EnvAPIKey), os.Getenv("API_KEY"))
 }